@@ -0,0 +1,136 @@
+package log
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// Default terminal size of asciicast recordings, when it can't be detected.
+const (
+	DefaultAsciicastWidth  = 80
+	DefaultAsciicastHeight = 24
+)
+
+// AsciicastOptions configures an AsciicastWriter.
+type AsciicastOptions struct {
+	// Width and Height are the terminal size of the recording. The default is
+	// the size of the terminal of os.Stderr, or DefaultAsciicastWidth and
+	// DefaultAsciicastHeight if it isn't a terminal.
+	Width, Height int
+	// Title is the title of the recording.
+	Title string
+	// Env are the environment variables of the recording, e.g. TERM. The
+	// default is TERM and SHELL from the environment.
+	Env map[string]string
+}
+
+// asciicastHeader is the first line of an asciicast v2 recording.
+type asciicastHeader struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// AsciicastWriter records the output written to it as an asciicast v2
+// recording, that can be replayed with players like asciinema. Each write is
+// an output event, timed relative to the creation of the writer. Use it as a
+// logger Recorder to record the styled output:
+//
+//	f, _ := os.Create("logs.cast")
+//	logger := log.NewWithOptions(os.Stderr, log.Options{
+//		Recorder: log.NewAsciicastWriter(f, log.AsciicastOptions{}),
+//	})
+//
+// It's safe to use from multiple loggers.
+type AsciicastWriter struct {
+	mu      sync.Mutex
+	w       io.Writer
+	header  asciicastHeader
+	started bool
+	start   time.Time
+	now     func() time.Time
+}
+
+// NewAsciicastWriter returns a writer that writes an asciicast recording to
+// w. The header is written with the first event.
+func NewAsciicastWriter(w io.Writer, o AsciicastOptions) *AsciicastWriter {
+	width, height := o.Width, o.Height
+	if width <= 0 || height <= 0 {
+		tw, th := DefaultAsciicastWidth, DefaultAsciicastHeight
+		if fd := os.Stderr.Fd(); term.IsTerminal(fd) {
+			if cols, rows, err := term.GetSize(fd); err == nil {
+				tw, th = cols, rows
+			}
+		}
+		if width <= 0 {
+			width = tw
+		}
+		if height <= 0 {
+			height = th
+		}
+	}
+	env := o.Env
+	if env == nil {
+		env = map[string]string{}
+		for _, key := range []string{"TERM", "SHELL"} {
+			if v, ok := os.LookupEnv(key); ok {
+				env[key] = v
+			}
+		}
+	}
+
+	start := time.Now()
+	return &AsciicastWriter{
+		w: w,
+		header: asciicastHeader{
+			Version:   2,
+			Width:     width,
+			Height:    height,
+			Timestamp: start.Unix(),
+			Title:     o.Title,
+			Env:       env,
+		},
+		start: start,
+		now:   time.Now,
+	}
+}
+
+// Write writes p as an output event. Newlines are written as "\r\n", like a
+// terminal does, so that players return to the start of the line.
+func (c *AsciicastWriter) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var buf []byte
+	if !c.started {
+		header, err := json.Marshal(c.header)
+		if err != nil {
+			return 0, err //nolint:wrapcheck
+		}
+		buf = append(header, '\n')
+	}
+
+	elapsed := math.Round(c.now().Sub(c.start).Seconds()*1e6) / 1e6
+	data := strings.ReplaceAll(string(p), "\r\n", "\n")
+	data = strings.ReplaceAll(data, "\n", "\r\n")
+	event, err := json.Marshal([]any{max(elapsed, 0), "o", data})
+	if err != nil {
+		return 0, err //nolint:wrapcheck
+	}
+	buf = append(append(buf, event...), '\n')
+	if _, err := c.w.Write(buf); err != nil {
+		return 0, err //nolint:wrapcheck
+	}
+	c.started = true
+	return len(p), nil
+}
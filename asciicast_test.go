@@ -0,0 +1,90 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/colorprofile"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAsciicastWriter(t *testing.T) {
+	var buf bytes.Buffer
+	c := NewAsciicastWriter(&buf, AsciicastOptions{
+		Width:  100,
+		Height: 30,
+		Title:  "build",
+		Env:    map[string]string{"TERM": "xterm-256color"},
+	})
+	now := c.start
+	c.now = func() time.Time { return now }
+
+	_, err := c.Write([]byte("one\n"))
+	assert.NoError(t, err)
+	now = now.Add(1500 * time.Millisecond)
+	_, err = c.Write([]byte("\x1b[1mtwo\x1b[m\r\nthree\n"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, `{"version":2,"width":100,"height":30,"timestamp":`+
+		strconv.FormatInt(c.start.Unix(), 10)+`,"title":"build","env":{"TERM":"xterm-256color"}}`+"\n"+
+		`[0,"o","one\r\n"]`+"\n"+
+		`[1.5,"o","\u001b[1mtwo\u001b[m\r\nthree\r\n"]`+"\n",
+		buf.String())
+}
+
+func TestAsciicastWriterDefaults(t *testing.T) {
+	t.Setenv("TERM", "xterm")
+	var buf bytes.Buffer
+	c := NewAsciicastWriter(&buf, AsciicastOptions{Height: 10})
+	assert.Equal(t, 10, c.header.Height)
+	assert.Positive(t, c.header.Width)
+	assert.Equal(t, "xterm", c.header.Env["TERM"])
+}
+
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) { return 0, errors.New("boom") }
+
+func TestAsciicastWriterError(t *testing.T) {
+	c := NewAsciicastWriter(errWriter{}, AsciicastOptions{})
+	_, err := c.Write([]byte("a"))
+	assert.EqualError(t, err, "boom")
+	assert.False(t, c.started)
+}
+
+func TestRecorder(t *testing.T) {
+	var out, rec bytes.Buffer
+	l := NewWithOptions(&out, Options{Recorder: &rec})
+	l.SetColorProfile(colorprofile.ANSI256)
+	l.SetStyles(MinimalStyles())
+	l.Info("hello", "n", 1)
+	assert.Equal(t, "INFO hello n=1\n", out.String())
+	assert.Equal(t, out.String(), rec.String())
+
+	l.SetOptions(WithRecorder(nil))
+	l.Info("again")
+	assert.Equal(t, "INFO hello n=1\n", rec.String())
+}
+
+func TestRecorderColorProfile(t *testing.T) {
+	var out, rec bytes.Buffer
+	l := NewWithOptions(&out, Options{Recorder: &rec})
+	l.SetColorProfile(colorprofile.NoTTY)
+	st := MinimalStyles()
+	st.Message = lipgloss.NewStyle().Foreground(lipgloss.Color("#ff0000"))
+	l.SetStyles(st)
+	l.Info("hello")
+	assert.Equal(t, "INFO hello\n", out.String())
+	assert.Equal(t, "INFO "+st.Message.Render("hello")+"\n", rec.String())
+
+	out.Reset()
+	rec.Reset()
+	l.SetFormatter(CBORFormatter)
+	l.Info("hello")
+	assert.NotEmpty(t, out.String())
+	assert.Empty(t, rec.String())
+}
@@ -0,0 +1,46 @@
+package log
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+// DefaultHexDumpLimit is the default maximum number of bytes shown in the hex
+// dumps of the TextFormatter.
+const DefaultHexDumpLimit = 256
+
+// BytesEncoding is the encoding of []byte values in the JSONFormatter and
+// LogfmtFormatter.
+type BytesEncoding uint8
+
+const (
+	// Base64Bytes encodes []byte values with standard base64. This is the
+	// default.
+	Base64Bytes BytesEncoding = iota
+	// HexBytes encodes []byte values as lowercase hex.
+	HexBytes
+)
+
+// encodeBytes encodes b for the JSONFormatter and LogfmtFormatter.
+func (l *Logger) encodeBytes(b []byte) string {
+	if l.bytesEncoding == HexBytes {
+		return hex.EncodeToString(b)
+	}
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+// hexDump returns the lines of a `hexdump -C` style dump of b, showing at
+// most limit bytes. A negative limit shows all bytes.
+func hexDump(b []byte, limit int) []string {
+	var more int
+	if limit > 0 && len(b) > limit {
+		b, more = b[:limit], len(b)-limit
+	}
+
+	lines := strings.Split(strings.TrimSuffix(hex.Dump(b), "\n"), "\n")
+	if more > 0 {
+		lines = append(lines, truncationMarker(more))
+	}
+	return lines
+}
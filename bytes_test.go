@@ -0,0 +1,56 @@
+package log
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBytesText(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf)
+
+	l.Info("msg", "b", []byte("hello, world!\x00\x01"), "n", 1)
+	assert.Equal(t, "INFO msg\n"+
+		"  b=\n"+
+		"  │ 00000000  68 65 6c 6c 6f 2c 20 77  6f 72 6c 64 21 00 01     |hello, world!..|\n"+
+		" n=1\n", buf.String())
+
+	buf.Reset()
+	l.SetOptions(WithHexDumpLimit(4))
+	l.Info("msg", "b", []byte("hello"))
+	assert.Equal(t, "INFO msg\n"+
+		"  b=\n"+
+		"  │ 00000000  68 65 6c 6c                                       |hell|\n"+
+		"  │ …(+1 bytes)\n", buf.String())
+
+	buf.Reset()
+	l.Info("msg", "b", []byte{})
+	assert.Equal(t, "INFO msg b=[]\n", buf.String())
+}
+
+func TestBytesEncoding(t *testing.T) {
+	cases := []struct {
+		name      string
+		formatter Formatter
+		encoding  BytesEncoding
+		expected  string
+	}{
+		{"json base64", JSONFormatter, Base64Bytes, "{\"level\":\"info\",\"msg\":\"msg\",\"b\":\"aGk=\"}\n"},
+		{"json hex", JSONFormatter, HexBytes, "{\"level\":\"info\",\"msg\":\"msg\",\"b\":\"6869\"}\n"},
+		{"logfmt base64", LogfmtFormatter, Base64Bytes, "level=info msg=msg b=\"aGk=\"\n"},
+		{"logfmt hex", LogfmtFormatter, HexBytes, "level=info msg=msg b=6869\n"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			l := NewWithOptions(&buf, Options{
+				Formatter:     c.formatter,
+				BytesEncoding: c.encoding,
+			})
+			l.Info("msg", "b", []byte("hi"))
+			assert.Equal(t, c.expected, buf.String())
+		})
+	}
+}
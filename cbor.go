@@ -0,0 +1,509 @@
+package log
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// CBOR major types, see RFC 8949 section 3.1.
+const (
+	cborUint   byte = 0 << 5
+	cborNegint byte = 1 << 5
+	cborBytes  byte = 2 << 5
+	cborText   byte = 3 << 5
+	cborArray  byte = 4 << 5
+	cborMap    byte = 5 << 5
+	cborTag    byte = 6 << 5
+	cborSimple byte = 7 << 5
+)
+
+const (
+	cborFalse   = cborSimple | 20
+	cborTrue    = cborSimple | 21
+	cborNull    = cborSimple | 22
+	cborUndef   = cborSimple | 23
+	cborFloat16 = cborSimple | 25
+	cborFloat32 = cborSimple | 26
+	cborFloat64 = cborSimple | 27
+
+	cborTagDateTime = 0
+	cborTagEpoch    = 1
+
+	// cborMaxDepth is the maximum nesting of decoded arrays and maps.
+	cborMaxDepth = 64
+)
+
+// ErrInvalidCBOR is returned by CBORDecoder.Decode for malformed entries.
+var ErrInvalidCBOR = errors.New("invalid CBOR entry")
+
+// cborFormatter writes the entry as a CBOR map, prefixed with its length as
+// a 4-byte big-endian integer. Bare slog attributes are written as entries of
+// the map, with groups as nested maps.
+func (l *Logger) cborFormatter(keyvals ...any) {
+	fields := make([]any, 0, len(keyvals))
+	i := 0
+	for i < len(keyvals) {
+		switch kv := keyvals[i].(type) {
+		case slogAttr:
+			fields = append(fields, kv.Key, kv.Value)
+			i++
+		default:
+			if i+1 < len(keyvals) {
+				fields = append(fields, keyvals[i], keyvals[i+1])
+			}
+			i += 2
+		}
+	}
+
+	start := l.b.Len()
+	l.b.Write([]byte{0, 0, 0, 0})
+	writeCBORHead(&l.b, cborMap, uint64(len(fields)/2))
+	for i := 0; i < len(fields); i += 2 {
+		key, value := fields[i], fields[i+1]
+		switch key {
+		case l.keys.Timestamp:
+			if t, ok := value.(time.Time); ok && l.timeEncoder != nil {
+				value = l.timeEncoder(t)
+			}
+		case l.keys.Level:
+			if level, ok := value.(Level); ok {
+				value = l.encodeLevel(level)
+			}
+		}
+		writeCBORText(&l.b, yamlKeyString(key))
+		l.writeCBORValue(&l.b, value)
+	}
+	binary.BigEndian.PutUint32(l.b.Bytes()[start:], uint32(l.b.Len()-start-4)) //nolint:gosec
+}
+
+func writeCBORHead(b *bytes.Buffer, major byte, n uint64) {
+	switch {
+	case n < 24:
+		b.WriteByte(major | byte(n))
+	case n <= math.MaxUint8:
+		b.WriteByte(major | 24)
+		b.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		b.WriteByte(major | 25)
+		_ = binary.Write(b, binary.BigEndian, uint16(n))
+	case n <= math.MaxUint32:
+		b.WriteByte(major | 26)
+		_ = binary.Write(b, binary.BigEndian, uint32(n))
+	default:
+		b.WriteByte(major | 27)
+		_ = binary.Write(b, binary.BigEndian, n)
+	}
+}
+
+func writeCBORText(b *bytes.Buffer, s string) {
+	if !utf8.ValidString(s) {
+		s = strings.ToValidUTF8(s, string(utf8.RuneError))
+	}
+	writeCBORHead(b, cborText, uint64(len(s)))
+	b.WriteString(s)
+}
+
+func writeCBORInt(b *bytes.Buffer, n int64) {
+	if n < 0 {
+		writeCBORHead(b, cborNegint, uint64(-(n + 1)))
+		return
+	}
+	writeCBORHead(b, cborUint, uint64(n))
+}
+
+// writeCBORTime writes t as an epoch-based date/time if it has no fractional
+// seconds, or as an RFC 3339 date/time otherwise, so that it's decoded
+// without losing precision.
+func writeCBORTime(b *bytes.Buffer, t time.Time) {
+	if t.Nanosecond() == 0 {
+		writeCBORHead(b, cborTag, cborTagEpoch)
+		writeCBORInt(b, t.Unix())
+		return
+	}
+	writeCBORHead(b, cborTag, cborTagDateTime)
+	writeCBORText(b, t.Format(time.RFC3339Nano))
+}
+
+// writeCBORValue writes v with its native CBOR type. Values without one are
+// encoded like the JSONFormatter does, so that they honor json tags and
+// json.Marshaler.
+func (l *Logger) writeCBORValue(b *bytes.Buffer, v any) {
+	switch v := v.(type) {
+	case nil:
+		b.WriteByte(cborNull)
+	case string:
+		writeCBORText(b, v)
+	case bool:
+		if v {
+			b.WriteByte(cborTrue)
+		} else {
+			b.WriteByte(cborFalse)
+		}
+	case int:
+		writeCBORInt(b, int64(v))
+	case int8:
+		writeCBORInt(b, int64(v))
+	case int16:
+		writeCBORInt(b, int64(v))
+	case int32:
+		writeCBORInt(b, int64(v))
+	case int64:
+		writeCBORInt(b, v)
+	case uint:
+		writeCBORHead(b, cborUint, uint64(v))
+	case uint8:
+		writeCBORHead(b, cborUint, uint64(v))
+	case uint16:
+		writeCBORHead(b, cborUint, uint64(v))
+	case uint32:
+		writeCBORHead(b, cborUint, uint64(v))
+	case uint64:
+		writeCBORHead(b, cborUint, v)
+	case uintptr:
+		writeCBORHead(b, cborUint, uint64(v))
+	case float32:
+		b.WriteByte(cborFloat32)
+		_ = binary.Write(b, binary.BigEndian, math.Float32bits(v))
+	case float64:
+		b.WriteByte(cborFloat64)
+		_ = binary.Write(b, binary.BigEndian, math.Float64bits(v))
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			writeCBORInt(b, n)
+		} else if f, err := v.Float64(); err == nil {
+			l.writeCBORValue(b, f)
+		} else {
+			writeCBORText(b, v.String())
+		}
+	case time.Time:
+		writeCBORTime(b, v)
+	case []byte:
+		writeCBORHead(b, cborBytes, uint64(len(v)))
+		b.Write(v)
+	case stackTrace:
+		l.writeCBORValue(b, v.frames())
+	case slogLogValuer:
+		l.writeCBORSlogValue(b, v.LogValue())
+	case slogValue:
+		l.writeCBORSlogValue(b, v)
+	case error:
+		writeCBORText(b, v.Error())
+	case fmt.Stringer:
+		writeCBORText(b, v.String())
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		writeCBORHead(b, cborMap, uint64(len(keys)))
+		for _, k := range keys {
+			writeCBORText(b, k)
+			l.writeCBORValue(b, v[k])
+		}
+	case []any:
+		writeCBORHead(b, cborArray, uint64(len(v)))
+		for _, item := range v {
+			l.writeCBORValue(b, item)
+		}
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			writeCBORText(b, fmt.Sprintf("%+v", v))
+			return
+		}
+		d := json.NewDecoder(bytes.NewReader(data))
+		d.UseNumber()
+		var decoded any
+		if err := d.Decode(&decoded); err != nil {
+			writeCBORText(b, fmt.Sprintf("%+v", v))
+			return
+		}
+		l.writeCBORValue(b, decoded)
+	}
+}
+
+func (l *Logger) writeCBORSlogValue(b *bytes.Buffer, v slogValue) {
+	v = v.Resolve()
+	if v.Kind() != slogKindGroup {
+		l.writeCBORValue(b, v.Any())
+		return
+	}
+	attrs := v.Group()
+	writeCBORHead(b, cborMap, uint64(len(attrs)))
+	for _, attr := range attrs {
+		writeCBORText(b, attr.Key)
+		l.writeCBORSlogValue(b, attr.Value)
+	}
+}
+
+// CBORDecoder reads the entries written by the CBORFormatter. Use
+// Logger.Replay to render them with another formatter, e.g.
+//
+//	d := log.NewCBORDecoder(r)
+//	for {
+//		keyvals, err := d.Decode()
+//		if err != nil {
+//			break
+//		}
+//		logger.Replay(keyvals...)
+//	}
+type CBORDecoder struct {
+	r    io.Reader
+	data []byte
+}
+
+// NewCBORDecoder returns a decoder that reads entries from r.
+func NewCBORDecoder(r io.Reader) *CBORDecoder {
+	return &CBORDecoder{r: r}
+}
+
+// Decode reads the next entry, and returns its key-value pairs in order.
+// Integers are decoded as int64, or uint64 if they don't fit, floats as
+// float64, date/times as time.Time, byte strings as []byte, arrays as []any,
+// and maps as map[string]any. It returns io.EOF when there are no more
+// entries.
+func (d *CBORDecoder) Decode() ([]any, error) {
+	var size [4]byte
+	if _, err := io.ReadFull(d.r, size[:]); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, fmt.Errorf("%w: truncated length", ErrInvalidCBOR)
+		}
+		return nil, err //nolint:wrapcheck
+	}
+
+	// Copy instead of allocating the length up front, so that a corrupt
+	// length doesn't allocate more than what's there.
+	var buf bytes.Buffer
+	n := int64(binary.BigEndian.Uint32(size[:]))
+	if _, err := io.CopyN(&buf, d.r, n); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: truncated entry", ErrInvalidCBOR)
+		}
+		return nil, err //nolint:wrapcheck
+	}
+
+	d.data = buf.Bytes()
+	major, _, count, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+	if major != cborMap {
+		return nil, fmt.Errorf("%w: entry isn't a map", ErrInvalidCBOR)
+	}
+	keyvals := make([]any, 0, min(2*count, uint64(len(d.data))))
+	for range count {
+		key, err := d.key()
+		if err != nil {
+			return nil, err
+		}
+		value, err := d.value(1)
+		if err != nil {
+			return nil, err
+		}
+		keyvals = append(keyvals, key, value)
+	}
+	if len(d.data) > 0 {
+		return nil, fmt.Errorf("%w: trailing data", ErrInvalidCBOR)
+	}
+	return keyvals, nil
+}
+
+// head reads the major type, additional information, and argument of the
+// next data item. For floats, the argument is the raw value.
+func (d *CBORDecoder) head() (byte, byte, uint64, error) {
+	if len(d.data) == 0 {
+		return 0, 0, 0, fmt.Errorf("%w: unexpected end", ErrInvalidCBOR)
+	}
+	major, info := d.data[0]&0xe0, d.data[0]&0x1f
+	d.data = d.data[1:]
+
+	var size int
+	switch {
+	case info < 24:
+		return major, info, uint64(info), nil
+	case info <= 27:
+		size = 1 << (info - 24)
+	default:
+		return 0, 0, 0, fmt.Errorf("%w: unsupported additional information %d", ErrInvalidCBOR, info)
+	}
+	if len(d.data) < size {
+		return 0, 0, 0, fmt.Errorf("%w: unexpected end", ErrInvalidCBOR)
+	}
+	var n uint64
+	for _, c := range d.data[:size] {
+		n = n<<8 | uint64(c)
+	}
+	d.data = d.data[size:]
+	return major, info, n, nil
+}
+
+// bytes reads n bytes of a byte or text string.
+func (d *CBORDecoder) bytes(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)) {
+		return nil, fmt.Errorf("%w: unexpected end", ErrInvalidCBOR)
+	}
+	b := d.data[:n:n]
+	d.data = d.data[n:]
+	return b, nil
+}
+
+// key reads a map key. Keys that aren't text strings are converted to
+// strings.
+func (d *CBORDecoder) key() (string, error) {
+	v, err := d.value(1)
+	if err != nil {
+		return "", err
+	}
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	return fmt.Sprint(v), nil
+}
+
+func (d *CBORDecoder) value(depth int) (any, error) {
+	if depth > cborMaxDepth {
+		return nil, fmt.Errorf("%w: nested too deeply", ErrInvalidCBOR)
+	}
+
+	major, info, n, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+	switch major {
+	case cborUint:
+		if n > math.MaxInt64 {
+			return n, nil
+		}
+		return int64(n), nil
+	case cborNegint:
+		if n > math.MaxInt64 {
+			return nil, fmt.Errorf("%w: integer overflow", ErrInvalidCBOR)
+		}
+		return -1 - int64(n), nil
+	case cborBytes:
+		b, err := d.bytes(n)
+		if err != nil {
+			return nil, err
+		}
+		return bytes.Clone(b), nil
+	case cborText:
+		b, err := d.bytes(n)
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	case cborArray:
+		items := make([]any, 0, min(n, uint64(len(d.data))))
+		for range n {
+			item, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	case cborMap:
+		m := make(map[string]any, min(n, uint64(len(d.data))))
+		for range n {
+			key, err := d.key()
+			if err != nil {
+				return nil, err
+			}
+			value, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = value
+		}
+		return m, nil
+	case cborTag:
+		return d.tag(n, depth)
+	default:
+		return simple(info, n)
+	}
+}
+
+// tag reads the content of a tag. Date/times are decoded as time.Time, and
+// other tags are ignored.
+func (d *CBORDecoder) tag(tag uint64, depth int) (any, error) {
+	v, err := d.value(depth + 1)
+	if err != nil {
+		return nil, err
+	}
+	switch tag {
+	case cborTagDateTime:
+		if s, ok := v.(string); ok {
+			t, err := time.Parse(time.RFC3339Nano, s)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidCBOR, err)
+			}
+			return t, nil
+		}
+	case cborTagEpoch:
+		switch v := v.(type) {
+		case int64:
+			return time.Unix(v, 0), nil
+		case uint64:
+			return nil, fmt.Errorf("%w: time overflow", ErrInvalidCBOR)
+		case float64:
+			sec, frac := math.Modf(v)
+			usec := math.Round(frac * float64(time.Second/time.Microsecond))
+			return time.Unix(int64(sec), int64(usec)*int64(time.Microsecond)), nil
+		}
+	}
+	return v, nil
+}
+
+// simple decodes a simple value or a float, given the additional
+// information and argument of its head.
+func simple(info byte, n uint64) (any, error) {
+	switch info {
+	case cborFloat16 &^ cborSimple:
+		return float16(uint16(n)), nil
+	case cborFloat32 &^ cborSimple:
+		return float64(math.Float32frombits(uint32(n))), nil
+	case cborFloat64 &^ cborSimple:
+		return math.Float64frombits(n), nil
+	}
+	switch n {
+	case uint64(cborFalse &^ cborSimple):
+		return false, nil
+	case uint64(cborTrue &^ cborSimple):
+		return true, nil
+	case uint64(cborNull &^ cborSimple), uint64(cborUndef &^ cborSimple):
+		return nil, nil
+	}
+	return nil, fmt.Errorf("%w: unsupported simple value %d", ErrInvalidCBOR, n)
+}
+
+// float16 converts a half-precision float to a float64.
+func float16(h uint16) float64 {
+	exp := int(h>>10) & 0x1f
+	mant := float64(h & 0x3ff)
+	var f float64
+	switch exp {
+	case 0:
+		f = math.Ldexp(mant, -24)
+	case 0x1f:
+		f = math.Inf(1)
+		if mant != 0 {
+			f = math.NaN()
+		}
+	default:
+		f = math.Ldexp(mant+1024, exp-25)
+	}
+	if h&0x8000 != 0 {
+		f = -f
+	}
+	return f
+}
@@ -0,0 +1,35 @@
+//go:build go1.21
+// +build go1.21
+
+package log
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCBORSlogRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWithOptions(&buf, Options{Formatter: CBORFormatter})
+	l.Info("info",
+		slog.Group("g", slog.Int("a", 1), slog.Group("h", slog.String("b", "x"))),
+		slog.Bool("ok", true),
+	)
+
+	kvs, err := NewCBORDecoder(&buf).Decode()
+	require.NoError(t, err)
+	assert.Equal(t, []any{
+		"level", "info", "msg", "info",
+		"g", map[string]any{"a": int64(1), "h": map[string]any{"b": "x"}},
+		"ok", true,
+	}, kvs)
+
+	var out bytes.Buffer
+	jl := NewWithOptions(&out, Options{Formatter: JSONFormatter})
+	jl.Replay(kvs...)
+	assert.Equal(t, `{"level":"info","msg":"info","g":{"a":1,"h":{"b":"x"}},"ok":true}`+"\n", out.String())
+}
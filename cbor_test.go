@@ -0,0 +1,159 @@
+package log
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCBOR(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf)
+	l.SetFormatter(CBORFormatter)
+	l.Info("hi", "n", 1)
+	assert.Equal(t, []byte{
+		0, 0, 0, 22, // length
+		0xa3,                                                    // map(3)
+		0x65, 'l', 'e', 'v', 'e', 'l', 0x64, 'i', 'n', 'f', 'o', // level: info
+		0x63, 'm', 's', 'g', 0x62, 'h', 'i', // msg: hi
+		0x61, 'n', 0x01, // n: 1
+	}, buf.Bytes())
+}
+
+func TestCBORRoundTrip(t *testing.T) {
+	type point struct {
+		X int `json:"x"`
+	}
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 123456789, time.UTC)
+
+	var buf bytes.Buffer
+	l := New(&buf)
+	l.SetFormatter(CBORFormatter)
+	l.SetReportTimestamp(true)
+	l.SetTimeFunction(func(time.Time) time.Time { return ts })
+	l.Warn("first",
+		"int", -42, "big", uint64(math.MaxUint64), "float", 1.5, "f32", float32(0.25),
+		"bool", true, "nil", nil, "bytes", []byte{0x1b, '[', '0', 'm'},
+		"time", ts.Add(time.Hour), "dur", time.Second, "err", errors.New("boom"),
+		"struct", point{1}, "list", []any{"a", 2},
+	)
+	l.Error("second")
+
+	d := NewCBORDecoder(&buf)
+	kvs, err := d.Decode()
+	require.NoError(t, err)
+	assert.Len(t, kvs, 30)
+	assert.Equal(t, "time", kvs[0])
+	assert.True(t, ts.Equal(kvs[1].(time.Time)))
+	assert.Equal(t, []any{
+		"level", "warn", "msg", "first",
+		"int", int64(-42), "big", uint64(math.MaxUint64), "float", 1.5, "f32", 0.25,
+		"bool", true, "nil", nil, "bytes", []byte{0x1b, '[', '0', 'm'},
+	}, kvs[2:20])
+	assert.True(t, ts.Add(time.Hour).Equal(kvs[21].(time.Time)))
+	assert.Equal(t, []any{
+		"dur", "1s", "err", "boom",
+		"struct", map[string]any{"x": int64(1)}, "list", []any{"a", int64(2)},
+	}, kvs[22:])
+
+	kvs, err = d.Decode()
+	require.NoError(t, err)
+	assert.Equal(t, "second", kvs[5])
+
+	_, err = d.Decode()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestCBORReplay(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf)
+	l.SetFormatter(CBORFormatter)
+	l.SetPrefix("app")
+	l.Info("hello", "user", "bob")
+	l.Debug("hidden")
+	l.Print("plain")
+
+	var out bytes.Buffer
+	text := New(&out)
+	text.SetLevel(DebugLevel)
+	text.SetReportTimestamp(false)
+	d := NewCBORDecoder(&buf)
+	for {
+		kvs, err := d.Decode()
+		if err != nil {
+			require.ErrorIs(t, err, io.EOF)
+			break
+		}
+		text.Replay(kvs...)
+	}
+	assert.Equal(t, "INFO app: hello user=bob\napp: plain\n", out.String())
+}
+
+func TestCBORReplayLevel(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf)
+	l.SetLevel(WarnLevel)
+	l.Replay("level", "info", "msg", "skipped")
+	l.Replay("level", int64(8), "msg", "error")
+	l.Replay("level", "bogus", "msg", "kept")
+	assert.Equal(t, "ERRO error\nkept\n", buf.String())
+
+	cases := map[any]Level{
+		"WARN": WarnLevel, "info+2": InfoLevel + 2, "error-1": ErrorLevel - 1, "-3": DebugLevel + 1,
+		int64(-4): DebugLevel, 12.0: FatalLevel,
+	}
+	for v, expected := range cases {
+		level, ok := replayLevel(v)
+		assert.True(t, ok)
+		assert.Equal(t, expected, level)
+	}
+	_, ok := replayLevel("info+x")
+	assert.False(t, ok)
+}
+
+func TestCBORDecodeInvalid(t *testing.T) {
+	frame := func(b ...byte) []byte {
+		return binary.BigEndian.AppendUint32(nil, uint32(len(b))) //nolint:gosec
+	}
+	cases := []struct {
+		name string
+		data []byte
+	}{
+		{"truncated length", []byte{0, 0}},
+		{"truncated entry", append(frame(0xa1, 0x61, 'a'), 0xa1)},
+		{"not a map", append(frame(0x01), 0x01)},
+		{"unexpected end", append(frame(0xa1, 0x61), 0xa1, 0x61)},
+		{"trailing data", append(frame(0xa0, 0x00), 0xa0, 0x00)},
+		{"indefinite length", append(frame(0xbf), 0xbf)},
+		{"simple value", append(frame(0xa1, 0x61, 'a', 0xe0), 0xa1, 0x61, 'a', 0xe0)},
+		{"nested", append(frame(make([]byte, 70)...), append(append([]byte{0xa1, 0x61, 'a'},
+			bytes.Repeat([]byte{0x81}, 66)...), 0x00)...)},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := NewCBORDecoder(bytes.NewReader(c.data)).Decode()
+			assert.ErrorIs(t, err, ErrInvalidCBOR)
+		})
+	}
+}
+
+func TestCBORFloat16(t *testing.T) {
+	cases := map[uint16]float64{
+		0x3c00: 1,
+		0xc000: -2,
+		0x3555: 0.333251953125,
+		0x0001: 5.960464477539063e-08,
+		0x7c00: math.Inf(1),
+	}
+	for h, expected := range cases {
+		assert.Equal(t, expected, float16(h))
+	}
+	assert.True(t, math.IsNaN(float16(0x7e00)))
+}
@@ -0,0 +1,219 @@
+package log
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-logfmt/logfmt"
+)
+
+// DefaultRestColumn is the default header of the column with the leftover
+// fields of the CSVFormatter and TSVFormatter.
+const DefaultRestColumn = "fields"
+
+// RestFormat is the format of the column with the leftover fields of the
+// CSVFormatter and TSVFormatter.
+type RestFormat uint8
+
+const (
+	// RestJSON writes leftover fields as a JSON object. This is the default.
+	RestJSON RestFormat = iota
+	// RestLogfmt writes leftover fields as logfmt.
+	RestLogfmt
+	// RestDiscard drops leftover fields, and omits their column.
+	RestDiscard
+)
+
+// CSVOptions configures the CSVFormatter and TSVFormatter.
+type CSVOptions struct {
+	// Columns are the keys of the columns, e.g. "time", "level", or the key
+	// of a field. The attributes of slog groups have dotted keys, e.g.
+	// "req.method". Entries without a key have an empty cell. The default is
+	// the timestamp, level, prefix, and message keys.
+	Columns []string
+	// Header is whether a header row with the column keys is written before
+	// the first entry.
+	Header bool
+	// Rest is the format of the trailing column with the fields that aren't
+	// in Columns. The default is RestJSON.
+	Rest RestFormat
+	// RestColumn is the header of the trailing column. The default is
+	// DefaultRestColumn.
+	RestColumn string
+}
+
+// firstHeader reports whether the header of the CSVFormatter or
+// HTMLFormatter is due, and marks it as written.
+func (s *textState) firstHeader() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	first := !s.headerWritten
+	s.headerWritten = true
+	return first
+}
+
+// csvColumns returns the column keys.
+func (l *Logger) csvColumns() []string {
+	if len(l.csv.Columns) > 0 {
+		return l.csv.Columns
+	}
+	return []string{l.keys.Timestamp, l.keys.Level, l.keys.Prefix, l.keys.Message}
+}
+
+func (l *Logger) csvFormatter(comma rune, keyvals ...any) {
+	w := csv.NewWriter(&l.b)
+	w.Comma = comma
+	columns := l.csvColumns()
+
+	index := make(map[string]int, len(columns))
+	for i, c := range columns {
+		if _, ok := index[c]; !ok {
+			index[c] = i
+		}
+	}
+	row := make([]string, len(columns), len(columns)+1)
+	filled := make([]bool, len(columns))
+	var rest []any
+	fields := l.csvFields(keyvals)
+	for i := 0; i < len(fields); i += 2 {
+		key, value := fields[i].(string), fields[i+1]
+		c, ok := index[key]
+		if !ok || filled[c] {
+			rest = append(rest, key, value)
+			continue
+		}
+		filled[c] = true
+		row[c] = l.csvValue(key, value)
+	}
+	if l.csv.Rest != RestDiscard {
+		row = append(row, l.csvRest(rest))
+	}
+	_ = w.Write(row)
+	w.Flush()
+}
+
+// csvFields returns the fields of keyvals with string keys. Bare slog
+// attributes are expanded into keys and values, and the attributes of groups
+// are flattened into dotted keys, e.g. "req.method".
+func (l *Logger) csvFields(keyvals []any) []any {
+	fields := make([]any, 0, len(keyvals))
+	i := 0
+	for i < len(keyvals) {
+		switch kv := keyvals[i].(type) {
+		case slogAttr:
+			fields = appendCSVField(fields, kv.Key, kv.Value)
+			i++
+		default:
+			if i+1 < len(keyvals) {
+				fields = appendCSVField(fields, fmt.Sprint(keyvals[i]), keyvals[i+1])
+			}
+			i += 2
+		}
+	}
+	return fields
+}
+
+func appendCSVField(fields []any, key string, value any) []any {
+	var v slogValue
+	switch value := value.(type) {
+	case slogValue:
+		v = value.Resolve()
+	case slogLogValuer:
+		v = value.LogValue().Resolve()
+	default:
+		return append(fields, key, value)
+	}
+	if v.Kind() != slogKindGroup {
+		return append(fields, key, v.Any())
+	}
+	for _, attr := range v.Group() {
+		k := attr.Key
+		if key != "" {
+			k = key + "." + k
+		}
+		fields = appendCSVField(fields, k, attr.Value)
+	}
+	return fields
+}
+
+// writeCSVHeader inserts the header row before the entry in the buffer, if
+// it's the first entry with a header.
+func (l *Logger) writeCSVHeader() {
+	if l.formatter != CSVFormatter && l.formatter != TSVFormatter ||
+		!l.csv.Header || !l.state.firstHeader() {
+		return
+	}
+
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	if l.formatter == TSVFormatter {
+		w.Comma = '\t'
+	}
+	header := append([]string(nil), l.csvColumns()...)
+	if l.csv.Rest != RestDiscard {
+		rest := l.csv.RestColumn
+		if rest == "" {
+			rest = DefaultRestColumn
+		}
+		header = append(header, rest)
+	}
+	_ = w.Write(header)
+	w.Flush()
+
+	b.Write(l.b.Bytes())
+	l.b.Reset()
+	_, _ = b.WriteTo(&l.b)
+}
+
+// csvValue returns the cell of a value.
+func (l *Logger) csvValue(key string, value any) string {
+	switch v := value.(type) {
+	case time.Time:
+		if key == l.keys.Timestamp {
+			return fmt.Sprint(l.encodeTime(v))
+		}
+	case Level:
+		if key == l.keys.Level {
+			return fmt.Sprint(l.encodeLevel(v))
+		}
+	case []byte:
+		return l.encodeBytes(v)
+	case nil:
+		return ""
+	}
+	return fmt.Sprintf("%+v", value)
+}
+
+// csvRest returns the trailing cell with the leftover fields.
+func (l *Logger) csvRest(keyvals []any) string {
+	if len(keyvals) == 0 {
+		return ""
+	}
+
+	var b bytes.Buffer
+	switch l.csv.Rest {
+	case RestLogfmt:
+		e := logfmt.NewEncoder(&b)
+		for i := 0; i < len(keyvals); i += 2 {
+			value := keyvals[i+1]
+			if v, ok := value.([]byte); ok {
+				value = l.encodeBytes(v)
+			}
+			err := e.EncodeKeyval(keyvals[i], value)
+			if err != nil && errors.Is(err, logfmt.ErrUnsupportedValueType) {
+				_ = e.EncodeKeyval(keyvals[i], fmt.Sprintf("%+v", value))
+			}
+		}
+	default:
+		jw := &jsonWriter{w: &b}
+		jw.start()
+		for i := 0; i < len(keyvals); i += 2 {
+			l.jsonFormatterItem(jw, keyvals[i], keyvals[i+1])
+		}
+		jw.end()
+	}
+	return b.String()
+}
@@ -0,0 +1,30 @@
+//go:build go1.21
+// +build go1.21
+
+package log
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSVSlog(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWithOptions(&buf, Options{
+		Formatter: CSVFormatter,
+		CSV:       CSVOptions{Columns: []string{"level", "msg", "g.a"}},
+	})
+	l.Info("info",
+		slog.Group("g", slog.Int("a", 1), slog.Group("h", slog.String("b", "x"))),
+		slog.Bool("ok", true),
+	)
+	assert.Equal(t, "info,info,1,\"{\"\"g.h.b\"\":\"\"x\"\",\"\"ok\"\":true}\"\n", buf.String())
+
+	buf.Reset()
+	l.SetOptions(WithCSVOptions(CSVOptions{Columns: []string{"level", "msg"}, Rest: RestLogfmt}))
+	l.Info("info", "req", slog.GroupValue(slog.String("method", "GET")), slog.Int("n", 2))
+	assert.Equal(t, "info,info,req.method=GET n=2\n", buf.String())
+}
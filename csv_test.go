@@ -0,0 +1,98 @@
+package log
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSV(t *testing.T) {
+	cases := []struct {
+		name     string
+		opts     CSVOptions
+		msg      string
+		kvs      []any
+		expected string
+	}{
+		{
+			name:     "simple",
+			msg:      "info",
+			expected: ",info,,info,\n",
+		},
+		{
+			name:     "rest json",
+			msg:      "info",
+			kvs:      []any{"a", 1, "b", "x y"},
+			expected: ",info,,info,\"{\"\"a\"\":1,\"\"b\"\":\"\"x y\"\"}\"\n",
+		},
+		{
+			name:     "rest logfmt",
+			opts:     CSVOptions{Rest: RestLogfmt},
+			msg:      "info",
+			kvs:      []any{"a", 1, "b", "x y"},
+			expected: ",info,,info,\"a=1 b=\"\"x y\"\"\"\n",
+		},
+		{
+			name:     "rest discard",
+			opts:     CSVOptions{Rest: RestDiscard},
+			msg:      "info",
+			kvs:      []any{"a", 1},
+			expected: ",info,,info\n",
+		},
+		{
+			name:     "quoting",
+			opts:     CSVOptions{Columns: []string{"msg"}, Rest: RestDiscard},
+			msg:      "a, \"b\"\nc",
+			expected: "\"a, \"\"b\"\"\nc\"\n",
+		},
+		{
+			name:     "columns",
+			opts:     CSVOptions{Columns: []string{"user", "missing", "msg"}},
+			msg:      "info",
+			kvs:      []any{"user", "bob", "user", "alice", "n", nil},
+			expected: "bob,,info,\"{\"\"level\"\":\"\"info\"\",\"\"user\"\":\"\"alice\"\",\"\"n\"\":null}\"\n",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			l := NewWithOptions(&buf, Options{Formatter: CSVFormatter, CSV: c.opts})
+			l.Info(c.msg, c.kvs...)
+			assert.Equal(t, c.expected, buf.String())
+		})
+	}
+}
+
+func TestCSVHeader(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWithOptions(&buf, Options{
+		Formatter: CSVFormatter,
+		CSV:       CSVOptions{Columns: []string{"level", "msg"}, Header: true, RestColumn: "rest"},
+	})
+	l.Info("one")
+	l.With("a", 1).Warn("two")
+	l.Error("three")
+	assert.Equal(t, "level,msg,rest\ninfo,one,\nwarn,two,\"{\"\"a\"\":1}\"\nerror,three,\n", buf.String())
+}
+
+func TestCSVHeaderEntrySize(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWithOptions(&buf, Options{
+		Formatter: CSVFormatter,
+		CSV:       CSVOptions{Columns: []string{"msg"}, Header: true, Rest: RestDiscard},
+		Limits:    Limits{MaxEntrySize: 16},
+	})
+	l.Info("hello wonderful world")
+	assert.Equal(t, "msg\nh…(+20 bytes)\n", buf.String())
+}
+
+func TestTSV(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWithOptions(&buf, Options{
+		Formatter: TSVFormatter,
+		CSV:       CSVOptions{Header: true, Rest: RestLogfmt},
+	})
+	l.Info("hello world", "a", 1)
+	assert.Equal(t, "time\tlevel\tprefix\tmsg\tfields\n\tinfo\t\thello world\ta=1\n", buf.String())
+}
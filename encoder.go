@@ -0,0 +1,171 @@
+package log
+
+import (
+	"fmt"
+	"time"
+)
+
+// TimeEncoder encodes a timestamp for a formatter. The JSON formatter writes
+// the returned value as-is, so numeric encodings stay numbers.
+type TimeEncoder func(time.Time) any
+
+// RFC3339NanoTimeEncoder encodes timestamps using time.RFC3339Nano.
+func RFC3339NanoTimeEncoder(t time.Time) any {
+	return t.Format(time.RFC3339Nano)
+}
+
+// UnixTimeEncoder encodes timestamps as seconds since the Unix epoch.
+func UnixTimeEncoder(t time.Time) any {
+	return t.Unix()
+}
+
+// UnixMilliTimeEncoder encodes timestamps as milliseconds since the Unix
+// epoch.
+func UnixMilliTimeEncoder(t time.Time) any {
+	return t.UnixMilli()
+}
+
+// UnixNanoTimeEncoder encodes timestamps as nanoseconds since the Unix epoch.
+func UnixNanoTimeEncoder(t time.Time) any {
+	return t.UnixNano()
+}
+
+// LayoutTimeEncoder returns a time encoder that formats timestamps using the
+// given layout.
+func LayoutTimeEncoder(layout string) TimeEncoder {
+	return func(t time.Time) any {
+		return t.Format(layout)
+	}
+}
+
+// UTCTimeEncoder returns a time encoder that converts timestamps to UTC before
+// encoding them with enc.
+func UTCTimeEncoder(enc TimeEncoder) TimeEncoder {
+	return func(t time.Time) any {
+		return enc(t.UTC())
+	}
+}
+
+// defaultMachineTimeEncoder is the default time encoder for the machine
+// formatters.
+var defaultMachineTimeEncoder = UTCTimeEncoder(RFC3339NanoTimeEncoder)
+
+// encodeTime encodes t using the logger time encoder. Without one, the
+// TextFormatter and HTMLFormatter use the logger time format, while the JSON,
+// Logfmt, YAML, CSV, and TSV formatters use RFC3339Nano in UTC, unless the
+// time format was changed from DefaultTimeFormat. The CBORFormatter only uses
+// the time encoder, and writes CBOR date/times without one.
+func (l *Logger) encodeTime(t time.Time) any {
+	switch {
+	case l.timeEncoder != nil:
+		return l.timeEncoder(t)
+	case l.formatter == TextFormatter, l.formatter == HTMLFormatter,
+		l.timeFormat != DefaultTimeFormat:
+		return t.Format(l.timeFormat)
+	default:
+		return defaultMachineTimeEncoder(t)
+	}
+}
+
+// LevelEncoder encodes a level for a formatter. The JSON formatter writes the
+// returned value as-is, so numeric encodings stay numbers.
+type LevelEncoder func(Level) any
+
+// LowercaseLevelEncoder encodes levels as lowercase names, e.g. "warn". Levels
+// in between the predefined ones are encoded relative to the closest lower
+// level, e.g. "info+2".
+func LowercaseLevelEncoder(level Level) any {
+	return levelName(level, DebugLevel.String(), InfoLevel.String(),
+		WarnLevel.String(), ErrorLevel.String(), FatalLevel.String())
+}
+
+// UppercaseLevelEncoder encodes levels as uppercase names, e.g. "WARN". Levels
+// in between the predefined ones are encoded relative to the closest lower
+// level, e.g. "INFO+2".
+func UppercaseLevelEncoder(level Level) any {
+	return levelName(level, "DEBUG", "INFO", "WARN", "ERROR", "FATAL")
+}
+
+// FullNameLevelEncoder encodes levels as full uppercase names, e.g. "WARNING".
+// Levels in between the predefined ones are encoded relative to the closest
+// lower level, e.g. "INFO+2".
+func FullNameLevelEncoder(level Level) any {
+	return levelName(level, "DEBUG", "INFO", "WARNING", "ERROR", "FATAL")
+}
+
+// NumericLevelEncoder encodes levels as their numeric value, e.g. 4.
+func NumericLevelEncoder(level Level) any {
+	return int(level)
+}
+
+// SyslogLevelEncoder encodes levels as syslog severities (RFC 5424), e.g. 4
+// for WarnLevel. Levels in between the predefined ones get the severity of the
+// closest lower level.
+func SyslogLevelEncoder(level Level) any {
+	switch {
+	case level < InfoLevel:
+		return 7 // debug
+	case level < WarnLevel:
+		return 6 // informational
+	case level < ErrorLevel:
+		return 4 // warning
+	case level < FatalLevel:
+		return 3 // error
+	default:
+		return 2 // critical
+	}
+}
+
+// OTelLevelEncoder encodes levels as OpenTelemetry severity numbers, e.g. 13
+// for WarnLevel. Levels in between the predefined ones map to the severity
+// numbers in between, e.g. 11 for InfoLevel+2.
+func OTelLevelEncoder(level Level) any {
+	// DebugLevel is SeverityNumber 5 (DEBUG), InfoLevel is 9 (INFO), etc.
+	return min(max(int(level)+9, 1), 24) //nolint:mnd
+}
+
+// MapLevelEncoder returns a level encoder that encodes levels using the given
+// names, and falls back to enc for levels that aren't in the map. A nil enc
+// falls back to LowercaseLevelEncoder.
+func MapLevelEncoder(names map[Level]string, enc LevelEncoder) LevelEncoder {
+	if enc == nil {
+		enc = LowercaseLevelEncoder
+	}
+	return func(level Level) any {
+		if name, ok := names[level]; ok {
+			return name
+		}
+		return enc(level)
+	}
+}
+
+func levelName(level Level, debug, info, warn, err, fatal string) string {
+	str := func(base string, offset Level) string {
+		if offset == 0 {
+			return base
+		}
+		return fmt.Sprintf("%s%+d", base, offset)
+	}
+
+	switch {
+	case level < InfoLevel:
+		return str(debug, level-DebugLevel)
+	case level < WarnLevel:
+		return str(info, level-InfoLevel)
+	case level < ErrorLevel:
+		return str(warn, level-WarnLevel)
+	case level < FatalLevel:
+		return str(err, level-ErrorLevel)
+	default:
+		return str(fatal, level-FatalLevel)
+	}
+}
+
+// encodeLevel encodes level using the logger level encoder. Without one, the
+// machine formatters use LowercaseLevelEncoder.
+func (l *Logger) encodeLevel(level Level) any {
+	if l.levelEncoder != nil {
+		return l.levelEncoder(level)
+	}
+	return LowercaseLevelEncoder(level)
+}
@@ -0,0 +1,234 @@
+package log
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeEncoder(t *testing.T) {
+	ts := time.Date(2023, 1, 2, 3, 4, 5, 600, time.FixedZone("EST", -5*60*60))
+	cases := []struct {
+		name      string
+		formatter Formatter
+		format    string
+		encoder   TimeEncoder
+		expected  string
+	}{
+		{
+			name:      "json default",
+			formatter: JSONFormatter,
+			expected:  "{\"time\":\"2023-01-02T08:04:05.0000006Z\",\"msg\":\"info\"}\n",
+		},
+		{
+			name:      "json time format",
+			formatter: JSONFormatter,
+			format:    time.Kitchen,
+			expected:  "{\"time\":\"3:04AM\",\"msg\":\"info\"}\n",
+		},
+		{
+			name:      "json unix",
+			formatter: JSONFormatter,
+			encoder:   UnixTimeEncoder,
+			expected:  "{\"time\":1672646645,\"msg\":\"info\"}\n",
+		},
+		{
+			name:      "json unix milli",
+			formatter: JSONFormatter,
+			encoder:   UnixMilliTimeEncoder,
+			expected:  "{\"time\":1672646645000,\"msg\":\"info\"}\n",
+		},
+		{
+			name:      "json unix nano",
+			formatter: JSONFormatter,
+			encoder:   UnixNanoTimeEncoder,
+			expected:  "{\"time\":1672646645000000600,\"msg\":\"info\"}\n",
+		},
+		{
+			name:      "json rfc3339nano",
+			formatter: JSONFormatter,
+			encoder:   RFC3339NanoTimeEncoder,
+			expected:  "{\"time\":\"2023-01-02T03:04:05.0000006-05:00\",\"msg\":\"info\"}\n",
+		},
+		{
+			name:      "logfmt default",
+			formatter: LogfmtFormatter,
+			expected:  "time=2023-01-02T08:04:05.0000006Z msg=info\n",
+		},
+		{
+			name:      "logfmt unix",
+			formatter: LogfmtFormatter,
+			encoder:   UnixTimeEncoder,
+			expected:  "time=1672646645 msg=info\n",
+		},
+		{
+			name:      "logfmt utc layout",
+			formatter: LogfmtFormatter,
+			encoder:   UTCTimeEncoder(LayoutTimeEncoder(time.DateTime)),
+			expected:  "time=\"2023-01-02 08:04:05\" msg=info\n",
+		},
+		{
+			name:      "text default",
+			formatter: TextFormatter,
+			expected:  "2023/01/02 03:04:05 info\n",
+		},
+		{
+			name:      "text unix",
+			formatter: TextFormatter,
+			encoder:   UnixTimeEncoder,
+			expected:  "1672646645 info\n",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			l := NewWithOptions(&buf, Options{
+				Formatter:       c.formatter,
+				ReportTimestamp: true,
+				TimeFormat:      c.format,
+				TimeEncoder:     c.encoder,
+				TimeFunction:    func(time.Time) time.Time { return ts },
+			})
+			l.Print("info")
+			require.Equal(t, c.expected, buf.String())
+		})
+	}
+}
+
+func TestLevelEncoders(t *testing.T) {
+	levels := []Level{DebugLevel - 1, DebugLevel, InfoLevel, InfoLevel + 2, WarnLevel, ErrorLevel, FatalLevel, FatalLevel + 20}
+	cases := []struct {
+		name     string
+		encoder  LevelEncoder
+		expected []any
+	}{
+		{
+			name:     "lowercase",
+			encoder:  LowercaseLevelEncoder,
+			expected: []any{"debug-1", "debug", "info", "info+2", "warn", "error", "fatal", "fatal+20"},
+		},
+		{
+			name:     "uppercase",
+			encoder:  UppercaseLevelEncoder,
+			expected: []any{"DEBUG-1", "DEBUG", "INFO", "INFO+2", "WARN", "ERROR", "FATAL", "FATAL+20"},
+		},
+		{
+			name:     "full name",
+			encoder:  FullNameLevelEncoder,
+			expected: []any{"DEBUG-1", "DEBUG", "INFO", "INFO+2", "WARNING", "ERROR", "FATAL", "FATAL+20"},
+		},
+		{
+			name:     "numeric",
+			encoder:  NumericLevelEncoder,
+			expected: []any{-5, -4, 0, 2, 4, 8, 12, 32},
+		},
+		{
+			name:     "syslog",
+			encoder:  SyslogLevelEncoder,
+			expected: []any{7, 7, 6, 6, 4, 3, 2, 2},
+		},
+		{
+			name:     "otel",
+			encoder:  OTelLevelEncoder,
+			expected: []any{4, 5, 9, 11, 13, 17, 21, 24},
+		},
+		{
+			name:     "map",
+			encoder:  MapLevelEncoder(map[Level]string{InfoLevel + 2: "notice"}, UppercaseLevelEncoder),
+			expected: []any{"DEBUG-1", "DEBUG", "INFO", "notice", "WARN", "ERROR", "FATAL", "FATAL+20"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual := make([]any, 0, len(levels))
+			for _, level := range levels {
+				actual = append(actual, c.encoder(level))
+			}
+			require.Equal(t, c.expected, actual)
+		})
+	}
+}
+
+func TestLevelEncoderFormatters(t *testing.T) {
+	cases := []struct {
+		name      string
+		formatter Formatter
+		encoder   LevelEncoder
+		level     Level
+		expected  string
+	}{
+		{
+			name:      "json numeric",
+			formatter: JSONFormatter,
+			encoder:   OTelLevelEncoder,
+			level:     WarnLevel,
+			expected:  "{\"level\":13,\"msg\":\"msg\"}\n",
+		},
+		{
+			name:      "json custom level",
+			formatter: JSONFormatter,
+			encoder:   FullNameLevelEncoder,
+			level:     InfoLevel + 2,
+			expected:  "{\"level\":\"INFO+2\",\"msg\":\"msg\"}\n",
+		},
+		{
+			name:      "json custom level without encoder",
+			formatter: JSONFormatter,
+			level:     InfoLevel + 2,
+			expected:  "{\"level\":\"info+2\",\"msg\":\"msg\"}\n",
+		},
+		{
+			name:      "logfmt custom level without encoder",
+			formatter: LogfmtFormatter,
+			level:     WarnLevel - 1,
+			expected:  "level=info+3 msg=msg\n",
+		},
+		{
+			name:      "logfmt",
+			formatter: LogfmtFormatter,
+			encoder:   FullNameLevelEncoder,
+			level:     WarnLevel,
+			expected:  "level=WARNING msg=msg\n",
+		},
+		{
+			name:      "logfmt syslog",
+			formatter: LogfmtFormatter,
+			encoder:   SyslogLevelEncoder,
+			level:     ErrorLevel,
+			expected:  "level=3 msg=msg\n",
+		},
+		{
+			name:      "text",
+			formatter: TextFormatter,
+			encoder:   FullNameLevelEncoder,
+			level:     WarnLevel,
+			expected:  "WARNING msg\n",
+		},
+		{
+			name:      "text custom level",
+			formatter: TextFormatter,
+			encoder:   LowercaseLevelEncoder,
+			level:     ErrorLevel + 1,
+			expected:  "error+1 msg\n",
+		},
+		{
+			name:      "text custom level without encoder",
+			formatter: TextFormatter,
+			level:     ErrorLevel + 1,
+			expected:  "msg\n",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			l := NewWithOptions(&buf, Options{
+				Formatter:    c.formatter,
+				LevelEncoder: c.encoder,
+			})
+			l.Log(c.level, "msg")
+			require.Equal(t, c.expected, buf.String())
+		})
+	}
+}
@@ -11,8 +11,28 @@ const (
 	JSONFormatter
 	// LogfmtFormatter is a formatter that formats log messages as logfmt.
 	LogfmtFormatter
+	// YAMLFormatter is a formatter that formats log messages as YAML
+	// documents, separated by "---".
+	YAMLFormatter
+	// CSVFormatter is a formatter that formats log messages as CSV rows. See
+	// CSVOptions for the columns.
+	CSVFormatter
+	// TSVFormatter is a formatter that formats log messages as
+	// tab-separated rows. See CSVOptions for the columns.
+	TSVFormatter
+	// CBORFormatter is a formatter that formats log messages as CBOR
+	// (RFC 8949) maps, each prefixed with its length as a 4-byte big-endian
+	// integer. Use CBORDecoder to read them back.
+	CBORFormatter
+	// HTMLFormatter is a formatter that formats log messages as HTML, with
+	// the TextFormatter layout and styles as inline CSS. See HTMLOptions for
+	// standalone documents.
+	HTMLFormatter
 )
 
+// The default keys for the built-in fields. Loggers copy these when they're
+// created, so changing them only affects loggers created afterwards. Use
+// KeyNames to configure the keys of a single logger.
 var (
 	// TimestampKey is the key for the timestamp.
 	TimestampKey = "time"
@@ -24,4 +44,80 @@ var (
 	CallerKey = "caller"
 	// PrefixKey is the key for the prefix.
 	PrefixKey = "prefix"
+	// DroppedFieldsKey is the key for the number of fields dropped by the
+	// logger Limits.
+	DroppedFieldsKey = "dropped_fields"
+	// MessageTemplateKey is the key for the template of messages logged with
+	// Logt, Infot, etc.
+	MessageTemplateKey = "msg_template"
+	// StackKey is the key for the stack trace.
+	StackKey = "stack"
 )
+
+// KeyNames defines the keys a logger uses for its built-in fields. Empty
+// names fall back to the package-level defaults.
+type KeyNames struct {
+	// Timestamp is the key for the timestamp. The default is TimestampKey.
+	Timestamp string
+	// Message is the key for the message. The default is MessageKey.
+	Message string
+	// Level is the key for the level. The default is LevelKey.
+	Level string
+	// Caller is the key for the caller. The default is CallerKey.
+	Caller string
+	// Prefix is the key for the prefix. The default is PrefixKey.
+	Prefix string
+	// DroppedFields is the key for the number of fields dropped by the
+	// logger Limits. The default is DroppedFieldsKey.
+	DroppedFields string
+	// MessageTemplate is the key for the template of messages logged with
+	// Logt, Infot, etc. The default is MessageTemplateKey.
+	MessageTemplate string
+	// Stack is the key for the stack trace. The default is StackKey.
+	Stack string
+}
+
+// DefaultKeyNames returns the key names from the package-level defaults.
+func DefaultKeyNames() KeyNames {
+	return KeyNames{
+		Timestamp:       TimestampKey,
+		Message:         MessageKey,
+		Level:           LevelKey,
+		Caller:          CallerKey,
+		Prefix:          PrefixKey,
+		DroppedFields:   DroppedFieldsKey,
+		MessageTemplate: MessageTemplateKey,
+		Stack:           StackKey,
+	}
+}
+
+// withDefaults returns a copy of k where empty names are replaced with the
+// package-level defaults.
+func (k KeyNames) withDefaults() KeyNames {
+	d := DefaultKeyNames()
+	if k.Timestamp == "" {
+		k.Timestamp = d.Timestamp
+	}
+	if k.Message == "" {
+		k.Message = d.Message
+	}
+	if k.Level == "" {
+		k.Level = d.Level
+	}
+	if k.Caller == "" {
+		k.Caller = d.Caller
+	}
+	if k.Prefix == "" {
+		k.Prefix = d.Prefix
+	}
+	if k.DroppedFields == "" {
+		k.DroppedFields = d.DroppedFields
+	}
+	if k.MessageTemplate == "" {
+		k.MessageTemplate = d.MessageTemplate
+	}
+	if k.Stack == "" {
+		k.Stack = d.Stack
+	}
+	return k
+}
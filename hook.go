@@ -0,0 +1,100 @@
+package log
+
+import (
+	"fmt"
+	"runtime"
+	"sync/atomic"
+	"time"
+)
+
+// Hook is a function that gets called for every entry a logger writes. It
+// receives the entry level, message, and key-value pairs, including the
+// logger fields.
+//
+// Hooks are called before the entry is formatted and must not modify
+// keyvals.
+type Hook func(level Level, msg string, keyvals []any)
+
+// fire calls the logger hooks, if the logger writes the entry, and forwards
+// the entry to the logger sinks. The timestamp is the time of the entry before
+// the logger time function is applied, so that each sink applies its own.
+func (l *Logger) fire(level Level, ts time.Time, frames []runtime.Frame, msg any, keyvals []any) {
+	hooks := len(l.hooks) > 0 && l.enabled(level)
+	if !hooks && len(l.sinks) == 0 {
+		return
+	}
+
+	fields := make([]any, 0, len(l.fields)+len(keyvals)+2)
+	fields = append(fields, l.fields...)
+	if len(l.fields)%2 != 0 {
+		fields = append(fields, ErrMissingValue)
+	}
+	fields = append(fields, keyvals...)
+	if len(keyvals)%2 != 0 {
+		fields = append(fields, ErrMissingValue)
+	}
+
+	if hooks {
+		var m string
+		if msg != nil {
+			m = fmt.Sprint(msg)
+		}
+		for _, hook := range l.hooks {
+			hook(level, m, fields)
+		}
+	}
+
+	for _, sink := range l.sinks {
+		if sink.wantsLevel(level) {
+			sink.handle(level, ts, frames, msg, fields...)
+		}
+	}
+}
+
+// hasSink reports whether s is the logger, or one of its sinks, directly or
+// through other sinks.
+func (l *Logger) hasSink(s *Logger) bool {
+	if l == s {
+		return true
+	}
+	for _, sink := range l.sinks {
+		if sink.hasSink(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// enabled reports whether the logger writes entries at the given level.
+func (l *Logger) enabled(level Level) bool {
+	return atomic.LoadUint32(&l.isDiscard) == 0 &&
+		atomic.LoadInt64(&l.level) <= int64(level)
+}
+
+// wantsLevel reports whether the logger, or any of its sinks, writes entries
+// at the given level.
+func (l *Logger) wantsLevel(level Level) bool {
+	if l.enabled(level) {
+		return true
+	}
+	for _, sink := range l.sinks {
+		if sink.wantsLevel(level) {
+			return true
+		}
+	}
+	return false
+}
+
+// wantsCaller reports whether the logger, or any of its sinks, reports the
+// caller location.
+func (l *Logger) wantsCaller() bool {
+	if l.reportCaller {
+		return true
+	}
+	for _, sink := range l.sinks {
+		if sink.wantsCaller() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package log
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHooks(t *testing.T) {
+	type entry struct {
+		level   Level
+		msg     string
+		keyvals []any
+	}
+	var entries []entry
+	hook := func(level Level, msg string, keyvals []any) {
+		entries = append(entries, entry{level, msg, keyvals})
+	}
+
+	var buf bytes.Buffer
+	l := NewWith(&buf, WithHooks(hook), WithFields("foo", "bar"))
+	l.Info("info", "baz")
+	l.Debug("debug")
+
+	require.Equal(t, []entry{
+		{InfoLevel, "info", []any{"foo", "bar", "baz", ErrMissingValue}},
+	}, entries)
+	require.Equal(t, "INFO info foo=bar baz=\"missing value\"\n", buf.String())
+}
+
+func TestSinks(t *testing.T) {
+	var buf, jsonBuf, debugBuf bytes.Buffer
+	jsonSink := NewWith(&jsonBuf, WithFormatter(JSONFormatter), WithLevel(WarnLevel))
+	debugSink := NewWith(&debugBuf, WithLevel(DebugLevel), WithLoggerPrefix("sink"))
+	l := NewWith(&buf, WithSinks(jsonSink, debugSink)).With("foo", "bar")
+
+	l.Debug("debug")
+	l.Info("info")
+	l.Warn("warn", "n", 1)
+
+	require.Equal(t, "INFO info foo=bar\nWARN warn foo=bar n=1\n", buf.String())
+	require.Equal(t, "{\"level\":\"warn\",\"msg\":\"warn\",\"foo\":\"bar\",\"n\":1}\n", jsonBuf.String())
+	require.Equal(t, "DEBU sink: debug foo=bar\nINFO sink: info foo=bar\nWARN sink: warn foo=bar n=1\n", debugBuf.String())
+}
+
+func TestSinkDiscard(t *testing.T) {
+	var sinkBuf bytes.Buffer
+	var hooked bool
+	sink := NewWith(&sinkBuf, WithLevel(DebugLevel))
+	l := NewWith(io.Discard, WithSinks(sink), WithHooks(func(Level, string, []any) {
+		hooked = true
+	}))
+
+	l.Debug("debug")
+
+	require.Equal(t, "DEBU debug\n", sinkBuf.String())
+	require.False(t, hooked)
+}
+
+func TestSinkTimeFunction(t *testing.T) {
+	var buf, sinkBuf bytes.Buffer
+	var received time.Time
+	ts := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	sink := NewWith(&sinkBuf, WithTimeFunction(func(t time.Time) time.Time {
+		received = t
+		return ts.Add(time.Hour)
+	}), WithReportTimestamp(true), WithTimeFormat(time.Kitchen))
+	l := NewWith(&buf, WithTimeFunction(func(time.Time) time.Time {
+		return ts
+	}), WithReportTimestamp(true), WithTimeFormat(time.Kitchen), WithSinks(sink))
+
+	l.Info("info")
+
+	require.Equal(t, "12:00AM INFO info\n", buf.String())
+	require.Equal(t, "1:00AM INFO info\n", sinkBuf.String())
+	require.NotEqual(t, ts, received)
+}
+
+func TestSinkCycles(t *testing.T) {
+	var aBuf, bBuf bytes.Buffer
+	a := NewWith(&aBuf)
+	b := NewWith(&bBuf, WithSinks(a))
+
+	a.SetOptions(WithSinks(a, b))
+	b.SetOptions(WithSinks(b))
+	a.Info("a")
+	b.Info("b")
+
+	require.Empty(t, a.sinks)
+	require.Equal(t, []*Logger{a}, b.sinks)
+	require.Equal(t, "INFO a\nINFO b\n", aBuf.String())
+	require.Equal(t, "INFO b\n", bBuf.String())
+}
@@ -0,0 +1,353 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"image/color"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// DefaultHTMLTitle is the default title of standalone HTMLFormatter
+// documents.
+const DefaultHTMLTitle = "Logs"
+
+// HTMLOptions configures the HTMLFormatter.
+type HTMLOptions struct {
+	// Standalone is whether a document header, with a stylesheet and a
+	// filter box, is written before the first entry. The document isn't
+	// closed, so that entries can be appended; browsers render it as-is.
+	Standalone bool
+	// Title is the title of standalone documents. The default is
+	// DefaultHTMLTitle.
+	Title string
+}
+
+// htmlDocument is the header of standalone documents. The filter box hides
+// the entries that don't contain its text.
+const htmlDocument = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%s</title>
+<style>
+:root { color-scheme: light dark; }
+body { margin: 0; font-family: ui-monospace, Menlo, Consolas, monospace; }
+#filter { position: sticky; top: 0; width: 100%%; box-sizing: border-box; padding: .5em; font: inherit; }
+.log-entry { margin: 0; padding: 0 .5em; white-space: pre-wrap; }
+.log-entry[hidden] { display: none; }
+</style>
+</head>
+<body>
+<input id="filter" type="search" placeholder="Filter" autofocus>
+<script>
+document.getElementById("filter").addEventListener("input", function (e) {
+  var q = e.target.value.toLowerCase();
+  document.querySelectorAll(".log-entry").forEach(function (el) {
+    el.hidden = q !== "" && el.textContent.toLowerCase().indexOf(q) < 0;
+  });
+});
+</script>
+`
+
+// htmlFormatter formats the entry with the TextFormatter, and converts its
+// styles to inline CSS. Each entry is a <pre class="log-entry"> element,
+// with its level in the data-level attribute.
+func (l *Logger) htmlFormatter(level Level, keyvals ...any) {
+	start := l.b.Len()
+	l.textFormatter(level, keyvals...)
+	text := strings.TrimSuffix(string(l.b.Bytes()[start:]), "\n")
+	l.b.Truncate(start)
+
+	l.b.WriteString(`<pre class="log-entry"`)
+	if level != noLevel {
+		fmt.Fprintf(&l.b, ` data-level="%v"`, LowercaseLevelEncoder(level))
+	}
+	l.b.WriteByte('>')
+	writeHTML(&l.b, text)
+	l.b.WriteString("</pre>\n")
+}
+
+// writeHTMLHeader inserts the document header before the entry in the
+// buffer, if it's the first entry of a standalone document.
+func (l *Logger) writeHTMLHeader() {
+	if l.formatter != HTMLFormatter || !l.html.Standalone || !l.state.firstHeader() {
+		return
+	}
+
+	title := l.html.Title
+	if title == "" {
+		title = DefaultHTMLTitle
+	}
+	var b bytes.Buffer
+	fmt.Fprintf(&b, htmlDocument, html.EscapeString(title))
+	b.Write(l.b.Bytes())
+	l.b.Reset()
+	_, _ = b.WriteTo(&l.b)
+}
+
+// htmlStyle is the state of the SGR attributes.
+type htmlStyle struct {
+	fg, bg                         color.Color
+	bold, faint, italic, underline bool
+	blink, reverse, strikethrough  bool
+}
+
+// css returns the inline CSS of the style.
+func (s htmlStyle) css() string {
+	var css []string
+	fg, bg := s.fg, s.bg
+	if s.reverse {
+		fg, bg = bg, fg
+		if fg == nil {
+			css = append(css, "color:Canvas")
+		}
+		if bg == nil {
+			css = append(css, "background-color:CanvasText")
+		}
+	}
+	if fg != nil {
+		css = append(css, "color:"+htmlColor(fg))
+	}
+	if bg != nil {
+		css = append(css, "background-color:"+htmlColor(bg))
+	}
+	if s.bold {
+		css = append(css, "font-weight:bold")
+	}
+	if s.faint {
+		css = append(css, "opacity:.6")
+	}
+	if s.italic {
+		css = append(css, "font-style:italic")
+	}
+	var lines []string
+	if s.underline {
+		lines = append(lines, "underline")
+	}
+	if s.strikethrough {
+		lines = append(lines, "line-through")
+	}
+	if s.blink {
+		lines = append(lines, "blink")
+	}
+	if len(lines) > 0 {
+		css = append(css, "text-decoration:"+strings.Join(lines, " "))
+	}
+	return strings.Join(css, ";")
+}
+
+func htmlColor(c color.Color) string {
+	r, g, b, _ := c.RGBA()
+	return fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+}
+
+// writeHTML writes the escaped text of s, converting SGR attributes to spans
+// with inline CSS, and OSC 8 hyperlinks to anchors. Other escape sequences
+// are dropped. Consecutive runs of text with the same style share a span.
+func writeHTML(b *bytes.Buffer, s string) {
+	var style htmlStyle
+	var span string
+	var linked bool
+	closeSpan := func() {
+		if span != "" {
+			b.WriteString("</span>")
+			span = ""
+		}
+	}
+	for len(s) > 0 {
+		i := strings.IndexByte(s, ansi.ESC)
+		if i != 0 {
+			if i < 0 {
+				i = len(s)
+			}
+			if css := style.css(); css != span {
+				closeSpan()
+				if css != "" {
+					fmt.Fprintf(b, `<span style="%s">`, css)
+					span = css
+				}
+			}
+			b.WriteString(html.EscapeString(s[:i]))
+			s = s[i:]
+			continue
+		}
+
+		if len(s) < 2 {
+			break
+		}
+		switch s[1] {
+		case '[':
+			// CSI: parameters and intermediates, then a final byte.
+			end := 2
+			for end < len(s) && (s[end] < 0x40 || s[end] > 0x7e) {
+				end++
+			}
+			if end < len(s) && s[end] == 'm' {
+				style.apply(s[2:end])
+			}
+			s = s[min(end+1, len(s)):]
+		case ']':
+			// OSC: terminated by BEL or ST.
+			payload, rest := s[2:], ""
+			if end := strings.IndexAny(payload, "\a\x1b"); end >= 0 {
+				payload, rest = payload[:end], payload[end+1:]
+				if s[2+end] == ansi.ESC {
+					rest = strings.TrimPrefix(rest, "\\")
+				}
+			}
+			if parts := strings.SplitN(payload, ";", 3); len(parts) == 3 && parts[0] == "8" {
+				closeSpan()
+				if linked {
+					b.WriteString("</a>")
+					linked = false
+				}
+				if htmlSafeURL(parts[2]) {
+					fmt.Fprintf(b, `<a href="%s">`, html.EscapeString(parts[2]))
+					linked = true
+				}
+			}
+			s = rest
+		default:
+			s = s[2:]
+		}
+	}
+	closeSpan()
+	if linked {
+		b.WriteString("</a>")
+	}
+}
+
+// htmlSafeURL reports whether u can be used as a link, i.e. it's not empty
+// and its scheme can't run scripts.
+func htmlSafeURL(u string) bool {
+	if u == "" {
+		return false
+	}
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(parsed.Scheme) {
+	case "javascript", "vbscript", "data":
+		return false
+	}
+	return true
+}
+
+// apply applies the SGR parameters to the style.
+func (s *htmlStyle) apply(params string) {
+	if params == "" {
+		*s = htmlStyle{}
+		return
+	}
+	ps := strings.Split(params, ";")
+	for i := 0; i < len(ps); i++ {
+		// Sub-parameters, e.g. "4:3" for curly underlines.
+		p, sub, _ := strings.Cut(ps[i], ":")
+		n, err := strconv.Atoi(p)
+		if err != nil && p != "" {
+			continue
+		}
+		switch {
+		case n == 0:
+			*s = htmlStyle{}
+		case n == 1:
+			s.bold = true
+		case n == 2:
+			s.faint = true
+		case n == 3:
+			s.italic = true
+		case n == 4:
+			s.underline = sub != "0"
+		case n == 5 || n == 6:
+			s.blink = true
+		case n == 7:
+			s.reverse = true
+		case n == 9:
+			s.strikethrough = true
+		case n == 22:
+			s.bold, s.faint = false, false
+		case n == 23:
+			s.italic = false
+		case n == 24:
+			s.underline = false
+		case n == 25:
+			s.blink = false
+		case n == 27:
+			s.reverse = false
+		case n == 29:
+			s.strikethrough = false
+		case n >= 30 && n <= 37:
+			s.fg = ansi.BasicColor(n - 30) //nolint:gosec
+		case n >= 90 && n <= 97:
+			s.fg = ansi.BasicColor(n - 90 + 8) //nolint:gosec
+		case n == 39:
+			s.fg = nil
+		case n >= 40 && n <= 47:
+			s.bg = ansi.BasicColor(n - 40) //nolint:gosec
+		case n >= 100 && n <= 107:
+			s.bg = ansi.BasicColor(n - 100 + 8) //nolint:gosec
+		case n == 49:
+			s.bg = nil
+		case n == 38 || n == 48 || n == 58:
+			var c color.Color
+			if sub != "" {
+				c = sgrColor(strings.Split(sub, ":"))
+			} else {
+				var used int
+				c, used = sgrColorParams(ps[i+1:])
+				i += used
+			}
+			switch n {
+			case 38:
+				s.fg = c
+			case 48:
+				s.bg = c
+			}
+		}
+	}
+}
+
+// sgrColorParams reads an extended color from the parameters after a 38 or
+// 48, e.g. "5;n" or "2;r;g;b", and returns the number of parameters used.
+func sgrColorParams(ps []string) (color.Color, int) {
+	if len(ps) == 0 {
+		return nil, 0
+	}
+	switch ps[0] {
+	case "5":
+		if len(ps) < 2 {
+			return nil, len(ps)
+		}
+		return sgrColor(ps[:2]), 2
+	case "2":
+		if len(ps) < 4 {
+			return nil, len(ps)
+		}
+		return sgrColor(ps[:4]), 4
+	}
+	return nil, 1
+}
+
+// sgrColor returns the color of the parameters of an extended color, e.g.
+// ["5", "n"] or ["2", "r", "g", "b"]. Colon-separated RGB colors can have a
+// color space before the components.
+func sgrColor(ps []string) color.Color {
+	atoi := func(s string) uint8 {
+		n, _ := strconv.Atoi(s)
+		return uint8(min(max(n, 0), 255)) //nolint:gosec
+	}
+	switch {
+	case len(ps) == 2 && ps[0] == "5":
+		return ansi.IndexedColor(atoi(ps[1]))
+	case len(ps) >= 4 && ps[0] == "2":
+		rgb := ps[len(ps)-3:]
+		return color.RGBA{R: atoi(rgb[0]), G: atoi(rgb[1]), B: atoi(rgb[2]), A: 0xff}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package log
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"charm.land/lipgloss/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTML(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWithOptions(&buf, Options{
+		Formatter:  HTMLFormatter,
+		Hyperlinks: Hyperlinks{URLs: true},
+	})
+	st := MinimalStyles()
+	st.Levels[WarnLevel] = st.Levels[WarnLevel].Bold(true).Foreground(lipgloss.Color("#ff8800"))
+	st.Key = lipgloss.NewStyle().Faint(true)
+	st.Kinds[URLValue] = lipgloss.NewStyle().Underline(true)
+	l.SetStyles(st)
+
+	l.Warn("a <b> & 'c'", "url", "https://example.com/?a=1&b=2")
+	l.Info("multi", "text", "one\ntwo")
+	l.Print("plain")
+	assert.Equal(t,
+		`<pre class="log-entry" data-level="warn"><span style="color:#ff8800;font-weight:bold">WARN</span> a &lt;b&gt; &amp; &#39;c&#39; `+
+			`<span style="opacity:.6">url</span>=<a href="https://example.com/?a=1&amp;b=2">`+
+			`<span style="text-decoration:underline">&#34;https://example.com/?a=1&amp;b=2&#34;</span></a></pre>`+"\n"+
+			`<pre class="log-entry" data-level="info">INFO multi`+"\n"+
+			`  <span style="opacity:.6">text</span>=`+"\n"+
+			"  │ one\n  │ two</pre>\n"+
+			`<pre class="log-entry">plain</pre>`+"\n",
+		buf.String())
+}
+
+func TestHTMLStandalone(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWithOptions(&buf, Options{
+		Formatter: HTMLFormatter,
+		HTML:      HTMLOptions{Standalone: true, Title: "<build>"},
+	})
+	l.SetStyles(MinimalStyles())
+	l.Info("one")
+	l.With("a", 1).Info("two")
+
+	out := buf.String()
+	assert.True(t, strings.HasPrefix(out, "<!DOCTYPE html>\n"))
+	assert.Contains(t, out, "<title>&lt;build&gt;</title>")
+	assert.Contains(t, out, `<input id="filter"`)
+	assert.Equal(t, 1, strings.Count(out, "<!DOCTYPE html>"))
+	assert.True(t, strings.HasSuffix(out, "</script>\n"+
+		`<pre class="log-entry" data-level="info">INFO one</pre>`+"\n"+
+		`<pre class="log-entry" data-level="info">INFO two a=1</pre>`+"\n"))
+}
+
+func TestWriteHTML(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "plain",
+			input:    "a < b",
+			expected: "a &lt; b",
+		},
+		{
+			name:     "basic colors",
+			input:    "\x1b[31;42mred\x1b[39mdefault\x1b[0m reset",
+			expected: `<span style="color:#800000;background-color:#008000">red</span><span style="background-color:#008000">default</span> reset`,
+		},
+		{
+			name:     "extended colors",
+			input:    "\x1b[38;5;196ma\x1b[38;2;1;2;3mb\x1b[48:2::4:5:6mc\x1b[m",
+			expected: `<span style="color:#ff0000">a</span><span style="color:#010203">b</span><span style="color:#010203;background-color:#040506">c</span>`,
+		},
+		{
+			name:     "attributes",
+			input:    "\x1b[1;3;4;9ma\x1b[22;23;24;29mb",
+			expected: `<span style="font-weight:bold;font-style:italic;text-decoration:underline line-through">a</span>b`,
+		},
+		{
+			name:     "reverse",
+			input:    "\x1b[7;31ma",
+			expected: `<span style="color:Canvas;background-color:#800000">a</span>`,
+		},
+		{
+			name:     "links",
+			input:    "\x1b]8;;https://a.b/\x1b\\\x1b[1ma\x1b]8;;\x07b",
+			expected: `<a href="https://a.b/"><span style="font-weight:bold">a</span></a><span style="font-weight:bold">b</span>`,
+		},
+		{
+			name:     "unsafe link",
+			input:    "\x1b]8;;javascript:alert(1)\x07a\x1b]8;;\x07",
+			expected: "a",
+		},
+		{
+			name:     "other sequences",
+			input:    "\x1b[2Ka\x1b]0;title\x07b\x1b7c\x1b[",
+			expected: "abc",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			writeHTML(&buf, c.input)
+			assert.Equal(t, c.expected, buf.String())
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package log
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/colorprofile"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Caller URL templates for common targets. See Hyperlinks.CallerURL.
+const (
+	// FileCallerURL opens the caller file.
+	FileCallerURL = "file://{path}"
+	// VSCodeCallerURL opens the caller location in Visual Studio Code.
+	VSCodeCallerURL = "vscode://file/{path}:{line}"
+)
+
+// RepoCallerURL returns a caller URL template that links to a file of a
+// GitHub or GitLab style web repository at the given commit. Use
+// Hyperlinks.Root to set the directory of the repository on disk, e.g.
+//
+//	log.RepoCallerURL("https://github.com/charmbracelet/log", "main")
+func RepoCallerURL(repo, commit string) string {
+	return strings.TrimSuffix(repo, "/") + "/blob/" + commit + "/{relpath}#L{line}"
+}
+
+// Hyperlinks controls the OSC 8 hyperlinks of the TextFormatter. Links are
+// only written when the output is a terminal that supports colors, see
+// colorprofile.Detect. The HTMLFormatter always writes them, as anchors.
+type Hyperlinks struct {
+	// CallerURL is the URL template for caller locations. The "{path}"
+	// placeholder is replaced with the absolute path of the caller file,
+	// "{relpath}" with the path relative to Root, "{line}" with the line
+	// number, and "{function}" with the function name. Empty disables
+	// caller links.
+	CallerURL string
+	// Root is the directory "{relpath}" is relative to.
+	Root string
+	// URLs links values that are URLs.
+	URLs bool
+}
+
+// hyperlink is a caller location with its link, for the TextFormatter.
+type hyperlink struct {
+	text string
+	url  string
+}
+
+// hyperlinksSupported reports whether the output supports hyperlinks. The
+// HTMLFormatter converts them to anchors.
+func (l *Logger) hyperlinksSupported() bool {
+	return l.formatter == TextFormatter && l.w.Profile >= colorprofile.ANSI ||
+		l.formatter == HTMLFormatter
+}
+
+// callerURL returns the URL of the caller location, or an empty string if
+// caller links are disabled.
+func (l *Logger) callerURL(file string, line int, fn string) string {
+	tmpl := l.hyperlinks.CallerURL
+	if tmpl == "" || !l.hyperlinksSupported() {
+		return ""
+	}
+
+	path := filepath.ToSlash(file)
+	rel := path
+	if l.hyperlinks.Root != "" {
+		if r, err := filepath.Rel(l.hyperlinks.Root, file); err == nil {
+			rel = filepath.ToSlash(r)
+		}
+	}
+	return strings.NewReplacer(
+		"{path}", path,
+		"{relpath}", rel,
+		"{line}", strconv.Itoa(line),
+		"{function}", fn,
+	).Replace(tmpl)
+}
+
+// link wraps text in an OSC 8 hyperlink to url.
+func link(text, url string) string {
+	return ansi.SetHyperlink(url) + text + ansi.ResetHyperlink()
+}
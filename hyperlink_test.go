@@ -0,0 +1,89 @@
+package log
+
+import (
+	"bytes"
+	"net/url"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/charmbracelet/colorprofile"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCallerHyperlinks(t *testing.T) {
+	_, file, line, _ := runtime.Caller(0)
+	logMsg := func(l *Logger) { l.Print("msg") }
+	dir := filepath.Dir(file)
+	n := strconv.Itoa(line + 1)
+	cases := []struct {
+		name     string
+		tmpl     string
+		profile  colorprofile.Profile
+		expected string
+	}{
+		{
+			name:     "file",
+			tmpl:     FileCallerURL,
+			profile:  colorprofile.ANSI,
+			expected: "\x1b]8;;file://" + filepath.ToSlash(file) + "\x07<log/hyperlink_test.go:" + n + ">\x1b]8;;\x07 msg\n",
+		},
+		{
+			name:     "vscode",
+			tmpl:     VSCodeCallerURL,
+			profile:  colorprofile.TrueColor,
+			expected: "\x1b]8;;vscode://file/" + filepath.ToSlash(file) + ":" + n + "\x07<log/hyperlink_test.go:" + n + ">\x1b]8;;\x07 msg\n",
+		},
+		{
+			name:     "repo",
+			tmpl:     RepoCallerURL("https://github.com/charmbracelet/log/", "abc123"),
+			profile:  colorprofile.ANSI256,
+			expected: "\x1b]8;;https://github.com/charmbracelet/log/blob/abc123/hyperlink_test.go#L" + n + "\x07<log/hyperlink_test.go:" + n + ">\x1b]8;;\x07 msg\n",
+		},
+		{
+			name:     "not a terminal",
+			tmpl:     FileCallerURL,
+			profile:  colorprofile.NoTTY,
+			expected: "<log/hyperlink_test.go:" + n + "> msg\n",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			l := NewWithOptions(&buf, Options{
+				ReportCaller: true,
+				Hyperlinks:   Hyperlinks{CallerURL: c.tmpl, Root: dir},
+			})
+			l.SetStyles(MinimalStyles())
+			l.SetColorProfile(c.profile)
+			logMsg(l)
+			assert.Equal(t, c.expected, buf.String())
+		})
+	}
+}
+
+func TestURLHyperlinks(t *testing.T) {
+	u, _ := url.Parse("https://charm.land")
+	st := MinimalStyles()
+
+	var buf bytes.Buffer
+	l := New(&buf)
+	l.SetStyles(st)
+	l.SetOptions(WithHyperlinks(Hyperlinks{URLs: true}))
+	l.SetColorProfile(colorprofile.ANSI)
+	l.Print("msg", "u", u, "s", "https://charm.land/log", "n", "charm.land")
+	assert.Equal(t, "msg u=\x1b]8;;https://charm.land\x07https://charm.land\x1b]8;;\x07 "+
+		"s=\x1b]8;;https://charm.land/log\x07https://charm.land/log\x1b]8;;\x07 n=charm.land\n", buf.String())
+
+	buf.Reset()
+	l.SetColorProfile(colorprofile.Ascii)
+	l.Print("msg", "u", u)
+	assert.Equal(t, "msg u=https://charm.land\n", buf.String())
+
+	buf.Reset()
+	l.SetColorProfile(colorprofile.ANSI)
+	l.SetFormatter(JSONFormatter)
+	l.Print("msg", "u", u)
+	assert.Equal(t, "{\"msg\":\"msg\",\"u\":\"https://charm.land\"}\n", buf.String())
+}
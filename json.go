@@ -8,6 +8,7 @@ import (
 )
 
 func (l *Logger) jsonFormatter(keyvals ...any) {
+	start := l.b.Len()
 	jw := &jsonWriter{w: &l.b}
 	jw.start()
 
@@ -26,30 +27,31 @@ func (l *Logger) jsonFormatter(keyvals ...any) {
 	}
 
 	jw.end()
+	l.formatJSON(start)
 	l.b.WriteRune('\n')
 }
 
 func (l *Logger) jsonFormatterRoot(jw *jsonWriter, key, value any) {
 	switch key {
-	case TimestampKey:
+	case l.keys.Timestamp:
 		if t, ok := value.(time.Time); ok {
-			jw.objectItem(TimestampKey, t.Format(l.timeFormat))
+			jw.objectItem(l.keys.Timestamp, l.encodeTime(t))
 		}
-	case LevelKey:
+	case l.keys.Level:
 		if level, ok := value.(Level); ok {
-			jw.objectItem(LevelKey, level.String())
+			jw.objectItem(l.keys.Level, l.encodeLevel(level))
 		}
-	case CallerKey:
+	case l.keys.Caller:
 		if caller, ok := value.(string); ok {
-			jw.objectItem(CallerKey, caller)
+			jw.objectItem(l.keys.Caller, caller)
 		}
-	case PrefixKey:
+	case l.keys.Prefix:
 		if prefix, ok := value.(string); ok {
-			jw.objectItem(PrefixKey, prefix)
+			jw.objectItem(l.keys.Prefix, prefix)
 		}
-	case MessageKey:
+	case l.keys.Message:
 		if msg := value; msg != nil {
-			jw.objectItem(MessageKey, fmt.Sprint(msg))
+			jw.objectItem(l.keys.Message, fmt.Sprint(msg))
 		}
 	default:
 		l.jsonFormatterItem(jw, key, value)
@@ -66,8 +68,12 @@ func (l *Logger) jsonFormatterItem(jw *jsonWriter, key, value any) {
 		jw.objectKey(fmt.Sprint(k))
 	}
 	switch v := value.(type) {
+	case stackTrace:
+		jw.objectValue(v.frames())
 	case error:
 		jw.objectValue(v.Error())
+	case []byte:
+		jw.objectValue(l.encodeBytes(v))
 	case slogLogValuer:
 		l.writeSlogValue(jw, v.LogValue())
 	case slogValue:
@@ -92,6 +98,8 @@ func (l *Logger) writeSlogValue(jw *jsonWriter, v slogValue) {
 		_, jm := a.(json.Marshaler)
 		if err, ok := a.(error); ok && !jm {
 			jw.objectValue(err.Error())
+		} else if b, ok := a.([]byte); ok {
+			jw.objectValue(l.encodeBytes(b))
 		} else {
 			jw.objectValue(a)
 		}
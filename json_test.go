@@ -2,6 +2,7 @@ package log
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -9,7 +10,9 @@ import (
 	"runtime"
 	"testing"
 
+	"charm.land/lipgloss/v2"
 	"github.com/charmbracelet/colorprofile"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/stretchr/testify/require"
 )
 
@@ -181,7 +184,7 @@ func TestJsonTime(t *testing.T) {
 	logger.SetFormatter(JSONFormatter)
 	logger.SetReportTimestamp(true)
 	logger.Info("info")
-	require.Equal(t, "{\"time\":\"0002/01/01 00:00:00\",\"level\":\"info\",\"msg\":\"info\"}\n", buf.String())
+	require.Equal(t, "{\"time\":\"0002-01-01T00:00:00Z\",\"level\":\"info\",\"msg\":\"info\"}\n", buf.String())
 }
 
 func TestJsonPrefix(t *testing.T) {
@@ -205,7 +208,27 @@ func TestJsonCustomKey(t *testing.T) {
 	logger.SetFormatter(JSONFormatter)
 	logger.SetReportTimestamp(true)
 	logger.Info("info")
-	require.Equal(t, "{\"other-time\":\"0002/01/01 00:00:00\",\"level\":\"info\",\"msg\":\"info\"}\n", buf.String())
+	require.Equal(t, "{\"other-time\":\"0002-01-01T00:00:00Z\",\"level\":\"info\",\"msg\":\"info\"}\n", buf.String())
+}
+
+func TestJsonKeyNames(t *testing.T) {
+	var buf, other bytes.Buffer
+	logger := NewWithOptions(&buf, Options{
+		TimeFunction:    _zeroTime,
+		Formatter:       JSONFormatter,
+		ReportTimestamp: true,
+		KeyNames:        KeyNames{Timestamp: "@timestamp", Message: "message"},
+	})
+	otherLogger := NewWithOptions(&other, Options{Formatter: JSONFormatter})
+	logger.With("foo", "bar").Info("info")
+	otherLogger.Info("info")
+	require.Equal(t, "{\"@timestamp\":\"0002-01-01T00:00:00Z\",\"level\":\"info\",\"message\":\"info\",\"foo\":\"bar\"}\n", buf.String())
+	require.Equal(t, "{\"level\":\"info\",\"msg\":\"info\"}\n", other.String())
+
+	buf.Reset()
+	logger.SetOptions(WithKeyNames(KeyNames{Level: "severity"}))
+	logger.Info("info")
+	require.Equal(t, "{\"time\":\"0002-01-01T00:00:00Z\",\"severity\":\"info\",\"msg\":\"info\"}\n", buf.String())
 }
 
 func TestJsonWriter(t *testing.T) {
@@ -334,3 +357,45 @@ type invalidJSON struct{}
 func (invalidJSON) MarshalJSON() ([]byte, error) {
 	return nil, errors.New("invalid json error")
 }
+
+func TestJsonIndent(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWithOptions(&buf, Options{Formatter: JSONFormatter, JSON: JSONOptions{Indent: "  "}})
+	l.Info("msg", "a", []int{1, 2})
+	require.Equal(t, "{\n"+
+		"  \"level\": \"info\",\n"+
+		"  \"msg\": \"msg\",\n"+
+		"  \"a\": [\n"+
+		"    1,\n"+
+		"    2\n"+
+		"  ]\n"+
+		"}\n", buf.String())
+}
+
+func TestJsonHighlight(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWithOptions(&buf, Options{Formatter: JSONFormatter, JSON: JSONOptions{Highlight: true}})
+	l.SetColorProfile(colorprofile.ANSI)
+	st := MinimalStyles()
+	st.JSON.Key = lipgloss.NewStyle().Foreground(lipgloss.Color("4"))
+	st.JSON.String = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	st.JSON.Number = lipgloss.NewStyle().Foreground(lipgloss.Color("6"))
+	st.JSON.Bool = lipgloss.NewStyle().Foreground(lipgloss.Color("5"))
+	st.JSON.Null = lipgloss.NewStyle().Faint(true)
+	l.SetStyles(st)
+
+	l.Info("msg", "s", "a \"b\":", "n", -1.5e3, "ok", true, "nil", nil)
+	require.Equal(t, "{\x1b[34m\"level\"\x1b[m:\x1b[32m\"info\"\x1b[m,"+
+		"\x1b[34m\"msg\"\x1b[m:\x1b[32m\"msg\"\x1b[m,"+
+		"\x1b[34m\"s\"\x1b[m:\x1b[32m\"a \\\"b\\\":\"\x1b[m,"+
+		"\x1b[34m\"n\"\x1b[m:\x1b[36m-1500\x1b[m,"+
+		"\x1b[34m\"ok\"\x1b[m:\x1b[35mtrue\x1b[m,"+
+		"\x1b[34m\"nil\"\x1b[m:\x1b[2mnull\x1b[m}\n", buf.String())
+
+	// The output is still valid JSON once the colors are stripped.
+	buf.Reset()
+	l.SetStyles(DefaultStyles())
+	l.SetOptions(WithJSONOptions(JSONOptions{Indent: "\t"}))
+	l.Info("msg", "s", "a\tb", "m", map[string]any{"x": []any{1, "y", false}})
+	require.True(t, json.Valid([]byte(ansi.Strip(buf.String()))), buf.String())
+}
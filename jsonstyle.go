@@ -0,0 +1,120 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"charm.land/lipgloss/v2"
+)
+
+// JSONStyles defines the styles of the JSONFormatter syntax highlighting.
+type JSONStyles struct {
+	// Key is the style for object keys.
+	Key lipgloss.Style
+
+	// String is the style for string values.
+	String lipgloss.Style
+
+	// Number is the style for numbers.
+	Number lipgloss.Style
+
+	// Bool is the style for true and false.
+	Bool lipgloss.Style
+
+	// Null is the style for null.
+	Null lipgloss.Style
+
+	// Punctuation is the style for braces, brackets, colons and commas.
+	Punctuation lipgloss.Style
+}
+
+// JSONOptions configures the JSONFormatter.
+type JSONOptions struct {
+	// Highlight is whether keys and values are highlighted with the JSON
+	// styles. The default is false.
+	Highlight bool
+	// Indent is the indentation of the entries, e.g. "  ". The default is to
+	// write each entry on a single line.
+	Indent string
+}
+
+// formatJSON indents and highlights the JSON entry that starts at start in
+// the buffer, depending on the logger options.
+func (l *Logger) formatJSON(start int) {
+	if l.json.Indent == "" && !l.json.Highlight {
+		return
+	}
+
+	entry := bytes.Clone(l.b.Bytes()[start:])
+	if l.json.Indent != "" {
+		var b bytes.Buffer
+		if err := json.Indent(&b, entry, "", l.json.Indent); err == nil {
+			entry = b.Bytes()
+		}
+	}
+	if l.json.Highlight {
+		entry = highlightJSON(entry, &l.styles.JSON)
+	}
+	l.b.Truncate(start)
+	l.b.Write(entry)
+}
+
+// highlightJSON returns a copy of the valid JSON b with its tokens styled.
+// Whitespace is kept as-is, so that the output is the same JSON once the
+// styles are stripped.
+func highlightJSON(b []byte, st *JSONStyles) []byte {
+	var out bytes.Buffer
+	for i := 0; i < len(b); {
+		c := b[i]
+		j := i + 1
+		switch {
+		case c == '"':
+			for j < len(b) && b[j] != '"' {
+				if b[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			j = min(j+1, len(b))
+
+			// Strings followed by a colon are object keys.
+			k := j
+			for k < len(b) && isJSONSpace(b[k]) {
+				k++
+			}
+			style := st.String
+			if k < len(b) && b[k] == ':' {
+				style = st.Key
+			}
+			out.WriteString(style.Render(string(b[i:j])))
+		case c == '-' || c >= '0' && c <= '9':
+			for j < len(b) && isJSONNumber(b[j]) {
+				j++
+			}
+			out.WriteString(st.Number.Render(string(b[i:j])))
+		case c >= 'a' && c <= 'z':
+			for j < len(b) && b[j] >= 'a' && b[j] <= 'z' {
+				j++
+			}
+			style := st.Bool
+			if string(b[i:j]) == "null" {
+				style = st.Null
+			}
+			out.WriteString(style.Render(string(b[i:j])))
+		case isJSONSpace(c):
+			out.WriteByte(c)
+		default:
+			out.WriteString(st.Punctuation.Render(string(c)))
+		}
+		i = j
+	}
+	return out.Bytes()
+}
+
+func isJSONSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isJSONNumber(c byte) bool {
+	return c >= '0' && c <= '9' || c == '-' || c == '+' || c == '.' || c == 'e' || c == 'E'
+}
@@ -0,0 +1,142 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"charm.land/lipgloss/v2"
+)
+
+// ValueKind is the kind of a value, used to style values by their type.
+type ValueKind uint8
+
+// Value kinds.
+const (
+	// NumberValue is the kind of integers, floats, and complex numbers.
+	NumberValue ValueKind = iota
+	// BoolValue is the kind of booleans.
+	BoolValue
+	// NilValue is the kind of nil values.
+	NilValue
+	// DurationValue is the kind of time.Duration values.
+	DurationValue
+	// TimeValue is the kind of time.Time values.
+	TimeValue
+	// ErrorValue is the kind of errors.
+	ErrorValue
+	// URLValue is the kind of url.URL values, and strings that are absolute
+	// URLs.
+	URLValue
+	// PathValue is the kind of strings that look like file paths, e.g.
+	// "/etc/hosts", "./main.go", or "~/.config".
+	PathValue
+	// StringValue is the kind of strings that need quoting.
+	StringValue
+)
+
+var valueKindNames = [...]string{
+	NumberValue:   "number",
+	BoolValue:     "bool",
+	NilValue:      "nil",
+	DurationValue: "duration",
+	TimeValue:     "time",
+	ErrorValue:    "error",
+	URLValue:      "url",
+	PathValue:     "path",
+	StringValue:   "string",
+}
+
+// String returns the name of the value kind.
+func (k ValueKind) String() string {
+	if int(k) < len(valueKindNames) {
+		return valueKindNames[k]
+	}
+	return fmt.Sprintf("ValueKind(%d)", k)
+}
+
+// ErrInvalidValueKind is an error returned when parsing an invalid value kind
+// string.
+var ErrInvalidValueKind = errors.New("invalid value kind")
+
+// ParseValueKind converts a value kind name to a ValueKind.
+func ParseValueKind(name string) (ValueKind, error) {
+	for k, n := range valueKindNames {
+		if strings.EqualFold(name, n) {
+			return ValueKind(k), nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %q", ErrInvalidValueKind, name)
+}
+
+// valueKind returns the kind of v, and false if v has no specific kind.
+func valueKind(v any) (ValueKind, bool) {
+	switch v := v.(type) {
+	case nil:
+		return NilValue, true
+	case time.Duration:
+		return DurationValue, true
+	case time.Time, *time.Time:
+		return TimeValue, true
+	case error:
+		return ErrorValue, true
+	case url.URL, *url.URL:
+		return URLValue, true
+	case bool:
+		return BoolValue, true
+	case int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64, uintptr,
+		float32, float64, complex64, complex128:
+		return NumberValue, true
+	case string:
+		return stringKind(v)
+	}
+	return 0, false
+}
+
+// stringKind returns the kind of a string value.
+func stringKind(s string) (ValueKind, bool) {
+	switch {
+	case isURL(s):
+		return URLValue, true
+	case isPath(s):
+		return PathValue, true
+	case s == "" || needsQuoting(s):
+		return StringValue, true
+	}
+	return 0, false
+}
+
+func isURL(s string) bool {
+	if !strings.Contains(s, "://") {
+		return false
+	}
+	u, err := url.Parse(s)
+	return err == nil && u.Scheme != "" && (u.Host != "" || u.Path != "")
+}
+
+func isPath(s string) bool {
+	for _, prefix := range []string{"/", "./", "../", "~/"} {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// valueStyle returns the style for the value v of key. Styles for specific
+// keys take precedence over styles for value kinds, which take precedence
+// over the value style.
+func (s *Styles) valueStyle(key string, v any) lipgloss.Style {
+	if st, ok := s.Values[key]; ok {
+		return st
+	}
+	if k, ok := valueKind(v); ok {
+		if st, ok := s.Kinds[k]; ok {
+			return st
+		}
+	}
+	return s.Value
+}
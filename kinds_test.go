@@ -0,0 +1,124 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/colorprofile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValueKind(t *testing.T) {
+	u, _ := url.Parse("https://charm.land")
+	cases := []struct {
+		name  string
+		value any
+		kind  ValueKind
+		ok    bool
+	}{
+		{"int", 42, NumberValue, true},
+		{"float", 1.5, NumberValue, true},
+		{"bool", false, BoolValue, true},
+		{"nil", nil, NilValue, true},
+		{"duration", time.Second, DurationValue, true},
+		{"time", time.Time{}, TimeValue, true},
+		{"error", errors.New("boom"), ErrorValue, true},
+		{"url", u, URLValue, true},
+		{"url string", "https://charm.land/log", URLValue, true},
+		{"path", "/etc/hosts", PathValue, true},
+		{"relative path", "./main.go", PathValue, true},
+		{"home path", "~/.config", PathValue, true},
+		{"quoted string", "hello world", StringValue, true},
+		{"empty string", "", StringValue, true},
+		{"plain string", "hello", 0, false},
+		{"not a url", "a://", 0, false},
+		{"struct", struct{}{}, 0, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			kind, ok := valueKind(c.value)
+			assert.Equal(t, c.ok, ok)
+			assert.Equal(t, c.kind, kind)
+		})
+	}
+}
+
+func TestParseValueKind(t *testing.T) {
+	for k := range valueKindNames {
+		kind, err := ParseValueKind(ValueKind(k).String())
+		require.NoError(t, err)
+		assert.Equal(t, ValueKind(k), kind)
+	}
+	_, err := ParseValueKind("nope")
+	require.ErrorIs(t, err, ErrInvalidValueKind)
+	assert.Equal(t, "ValueKind(42)", ValueKind(42).String())
+}
+
+func TestKindStyles(t *testing.T) {
+	bold := lipgloss.NewStyle().Bold(true)
+	red := lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	st := MinimalStyles()
+	st.Kinds[NumberValue] = bold
+	st.Kinds[DurationValue] = red
+	st.Values["port"] = red
+
+	var buf bytes.Buffer
+	l := New(&buf)
+	l.SetColorProfile(colorprofile.ANSI)
+	l.SetStyles(st)
+	l.SetOptions(WithPrettyValues(PrettyValues{Enabled: true}))
+
+	l.Info("msg", "n", 1, "d", time.Second, "port", 8080, "s", "str")
+	assert.Equal(t, "INFO msg n=\x1b[1m1\x1b[m d=\x1b[31m1s\x1b[m port=\x1b[31m8080\x1b[m s=str\n", buf.String())
+
+	buf.Reset()
+	l.Info("msg", "ns", []any{1, "a"})
+	assert.Equal(t, "INFO msg\n  ns=\n  │ [\n  │   \x1b[1m1\x1b[m\n  │   a\n  │ ]\n", buf.String())
+}
+
+func TestKindStylesPrecedence(t *testing.T) {
+	red := lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	green := lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	blue := lipgloss.NewStyle().Foreground(lipgloss.Color("4"))
+	st := MinimalStyles()
+	st.Kinds[NumberValue] = red.Bold(true)
+	st.Value = green
+	st.Values["port"] = blue
+	yellow := lipgloss.NewStyle().Faint(true).Foreground(lipgloss.Color("3"))
+	st.LevelOverrides[ErrorLevel] = LevelStyles{Line: &yellow}
+
+	var buf bytes.Buffer
+	l := New(&buf)
+	l.SetColorProfile(colorprofile.ANSI)
+	l.SetStyles(st)
+
+	// Styles for specific keys take precedence over kind styles, which take
+	// precedence over the value style.
+	l.Info("msg", "n", 1, "port", 8080, "s", "str")
+	assert.Equal(t, "INFO msg n=\x1b[1;31m1\x1b[m port=\x1b[34m8080\x1b[m s=\x1b[32mstr\x1b[m\n", buf.String())
+
+	// Kind styles take precedence over the line style.
+	buf.Reset()
+	l.Error("msg", "n", 1)
+	assert.Equal(t, "\x1b[2;33mERRO\x1b[m \x1b[2;33mmsg\x1b[m \x1b[2;33mn\x1b[m\x1b[2;33m=\x1b[m\x1b[1;2;31m1\x1b[m\n", buf.String())
+}
+
+func TestHighContrastKinds(t *testing.T) {
+	st := HighContrastStyles()
+	var buf bytes.Buffer
+	l := New(&buf)
+	l.SetColorProfile(colorprofile.ANSI256)
+	l.SetStyles(st)
+
+	l.Info("msg", "n", 1, "ok", true, "err", errors.New("boom"), "s", "str")
+	assert.Contains(t, buf.String(), st.Kinds[NumberValue].Render("1"))
+	assert.Contains(t, buf.String(), st.Kinds[BoolValue].Render("true"))
+	assert.Contains(t, buf.String(), st.Kinds[ErrorValue].Render("boom"))
+	assert.Contains(t, buf.String(), st.Value.Render("str"))
+	assert.NotEqual(t, st.Value.Render("1"), st.Kinds[NumberValue].Render("1"))
+}
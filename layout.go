@@ -0,0 +1,283 @@
+package log
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/term"
+)
+
+// Alignment controls how the TextFormatter pads prefixes and messages, so
+// that the key-value pairs of consecutive entries start in the same column.
+// Widths are measured in terminal cells, ignoring ANSI escape sequences.
+type Alignment struct {
+	// PrefixWidth is the width prefixes are padded to. Zero disables prefix
+	// padding, unless Adaptive is set.
+	PrefixWidth int
+	// MessageWidth is the width messages are padded to. Zero disables message
+	// padding, unless Adaptive is set.
+	MessageWidth int
+	// Adaptive pads prefixes and messages to the widest ones seen so far,
+	// using PrefixWidth and MessageWidth as the minimum widths.
+	Adaptive bool
+}
+
+func (a Alignment) enabled() bool {
+	return a.Adaptive || a.PrefixWidth > 0 || a.MessageWidth > 0
+}
+
+// columnWidth returns the width to pad a column part of width w to. In
+// adaptive mode, it grows the column to the widest part seen so far.
+func (s *textState) columnWidth(col *int, minWidth, w int, adaptive bool) int {
+	if !adaptive {
+		return minWidth
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	*col = max(*col, minWidth, w)
+	return *col
+}
+
+// textColumns keeps track of the aligned columns of a single entry.
+type textColumns struct {
+	l   *Logger
+	pad int // spaces to write before the next part
+
+	prefixDone  bool
+	messageDone bool
+}
+
+// prefix pads the prefix part of width w.
+func (c *textColumns) prefix(w int) {
+	a := c.l.alignment
+	target := c.l.state.columnWidth(&c.l.state.prefixWidth, a.PrefixWidth, w, a.Adaptive)
+	c.pad += max(target-w, 0)
+	c.prefixDone = true
+}
+
+// beforeMessage pads the prefix column if the entry doesn't have a prefix.
+func (c *textColumns) beforeMessage() {
+	if !c.prefixDone {
+		c.skip(&c.l.state.prefixWidth, c.l.alignment.PrefixWidth)
+		c.prefixDone = true
+	}
+}
+
+// message pads the message part of width w.
+func (c *textColumns) message(w int) {
+	a := c.l.alignment
+	target := c.l.state.columnWidth(&c.l.state.messageWidth, a.MessageWidth, w, a.Adaptive)
+	c.pad += max(target-w, 0)
+	c.messageDone = true
+}
+
+// beforeField pads the prefix and message columns if the entry doesn't have
+// them.
+func (c *textColumns) beforeField() {
+	if !c.messageDone {
+		c.beforeMessage()
+		c.skip(&c.l.state.messageWidth, c.l.alignment.MessageWidth)
+		c.messageDone = true
+	}
+}
+
+// skip pads an empty column, including the space that separates it from the
+// next part.
+func (c *textColumns) skip(col *int, minWidth int) {
+	target := c.l.state.columnWidth(col, minWidth, 0, c.l.alignment.Adaptive)
+	if target > 0 {
+		c.pad += target + 1
+	}
+}
+
+// flush writes the pending padding.
+func (c *textColumns) flush() {
+	if c.pad > 0 {
+		c.l.b.WriteString(strings.Repeat(" ", c.pad))
+		c.pad = 0
+	}
+}
+
+// stringWidth returns the width of s in terminal cells, ignoring ANSI escape
+// sequences.
+func stringWidth(s string) int {
+	return ansi.StringWidth(s)
+}
+
+// Layout is the layout mode of the TextFormatter.
+type Layout uint8
+
+const (
+	// InlineLayout writes every part of an entry on a single line. This is
+	// the default.
+	InlineLayout Layout = iota
+	// TerminalLayout fits entries to the terminal width. The caller and
+	// timestamp are right-aligned, and fields that don't fit wrap onto
+	// continuation lines, indented under the message. The terminal width is
+	// checked again when the terminal is resized. Writers that aren't
+	// terminals use InlineLayout.
+	TerminalLayout
+)
+
+// textLine lays out a single entry in the TerminalLayout.
+type textLine struct {
+	width  int // terminal width
+	start  int // start of the entry in the buffer
+	indent int // column of the message
+
+	right  []string     // right-aligned parts
+	fields []string     // single-line fields
+	blocks bytes.Buffer // multiline fields
+}
+
+// first reports whether nothing has been written to the line yet. A nil line
+// falls back to firstKey.
+func (t *textLine) first(b *bytes.Buffer, firstKey bool) bool {
+	if t == nil {
+		return firstKey
+	}
+	return b.Len() == t.start
+}
+
+// message records the column of the message, which is written next.
+func (t *textLine) message(b *bytes.Buffer) {
+	if t == nil {
+		return
+	}
+	t.indent = stringWidth(b.String()[t.start:])
+}
+
+// write writes the fields and right-aligned parts after the header that's
+// already in the buffer, wrapping fields that don't fit.
+func (t *textLine) write(b *bytes.Buffer) {
+	lineWidth := stringWidth(b.String()[t.start:])
+	indent := t.indent
+	if indent == 0 || indent >= t.width/2 {
+		// No message, or the message starts too far right to be
+		// useful as the indentation of the continuation lines.
+		indent = min(lineWidth+1, 4) //nolint:mnd
+	}
+
+	right := strings.Join(t.right, " ")
+	rightWidth := stringWidth(right)
+	first := true
+	limit := t.width
+	if rightWidth > 0 {
+		limit -= rightWidth + 1
+	}
+
+	for _, f := range t.fields {
+		fw := stringWidth(f)
+		empty := (first && lineWidth == 0) || (!first && lineWidth == indent)
+		if !empty && lineWidth+1+fw > limit {
+			if first {
+				writeRight(b, right, t.width-lineWidth-rightWidth)
+				first = false
+				limit = t.width
+			}
+			b.WriteByte('\n')
+			b.WriteString(strings.Repeat(" ", indent))
+			lineWidth = indent
+			empty = true
+		}
+		if !empty {
+			b.WriteByte(' ')
+			lineWidth++
+		}
+		b.WriteString(f)
+		lineWidth += fw
+	}
+
+	if first {
+		writeRight(b, right, t.width-lineWidth-rightWidth)
+	}
+	b.Write(t.blocks.Bytes())
+}
+
+// writeRight writes the right-aligned parts after pad spaces, or a single
+// space if they don't fit.
+func writeRight(b *bytes.Buffer, right string, pad int) {
+	if right == "" {
+		return
+	}
+	b.WriteString(strings.Repeat(" ", max(pad, 1)))
+	b.WriteString(right)
+}
+
+// terminalWidth returns the width of the terminal w writes to, or 0 if w
+// isn't a terminal. The width is cached until the terminal is resized.
+func (s *textState) terminalWidth(w io.Writer) int {
+	f, ok := w.(interface{ Fd() uintptr })
+	if !ok {
+		return 0
+	}
+	fd := f.Fd()
+	gen := resizeGeneration()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.widthOK && s.widthFd == fd && s.widthGen == gen {
+		return s.width
+	}
+
+	s.width = 0
+	if term.IsTerminal(fd) {
+		if width, _, err := term.GetSize(fd); err == nil {
+			s.width = width
+		}
+	}
+	s.widthFd, s.widthGen, s.widthOK = fd, gen, true
+	return s.width
+}
+
+// textGroup keeps track of the timestamp and prefix of a single entry in
+// compact mode. Entries are grouped under a header line with their timestamp
+// and prefix, so their own are blanked out.
+type textGroup struct {
+	start     int // start of the entry in the buffer
+	ts        string
+	prefixStr string
+}
+
+// timestamp records the rendered timestamp, and returns it blanked out.
+func (g *textGroup) timestamp(ts string) string {
+	if g == nil {
+		return ts
+	}
+	g.ts = ts
+	return strings.Repeat(" ", stringWidth(ts))
+}
+
+// prefix records the rendered prefix, and returns it blanked out.
+func (g *textGroup) prefix(prefix string) string {
+	if g == nil {
+		return prefix
+	}
+	g.prefixStr = prefix
+	return strings.Repeat(" ", stringWidth(prefix))
+}
+
+// write writes a group header before the entry if its timestamp or prefix
+// differs from the previous entry.
+func (g *textGroup) write(b *bytes.Buffer, s *textState) {
+	s.mu.Lock()
+	changed := g.ts != s.lastTimestamp || g.prefixStr != s.lastPrefix
+	s.lastTimestamp, s.lastPrefix = g.ts, g.prefixStr
+	s.mu.Unlock()
+
+	if !changed || (g.ts == "" && g.prefixStr == "") {
+		return
+	}
+
+	entry := append([]byte(nil), b.Bytes()[g.start:]...)
+	b.Truncate(g.start)
+	b.WriteString(g.ts)
+	if g.ts != "" && g.prefixStr != "" {
+		b.WriteByte(' ')
+	}
+	b.WriteString(g.prefixStr)
+	b.WriteByte('\n')
+	b.Write(entry)
+}
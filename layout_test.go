@@ -0,0 +1,204 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/colorprofile"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlignment(t *testing.T) {
+	type entry struct {
+		prefix string
+		msg    string
+		kvs    []any
+	}
+	entries := []entry{
+		{"app", "starting", []any{"a", 1}},
+		{"", "ok", []any{"b", 2}},
+		{"database", "connecting to", []any{"c", 3}},
+		{"app", "", []any{"d", 4}},
+		{"app", "done", nil},
+	}
+	cases := []struct {
+		name      string
+		alignment Alignment
+		expected  string
+	}{
+		{
+			name: "no alignment",
+			expected: "INFO app: starting a=1\n" +
+				"INFO ok b=2\n" +
+				"INFO database: connecting to c=3\n" +
+				"INFO app: d=4\n" +
+				"INFO app: done\n",
+		},
+		{
+			name:      "fixed",
+			alignment: Alignment{PrefixWidth: 6, MessageWidth: 10},
+			expected: "INFO app:   starting   a=1\n" +
+				"INFO        ok         b=2\n" +
+				"INFO database: connecting to c=3\n" +
+				"INFO app:              d=4\n" +
+				"INFO app:   done\n",
+		},
+		{
+			name:      "fixed message only",
+			alignment: Alignment{MessageWidth: 10},
+			expected: "INFO app: starting   a=1\n" +
+				"INFO ok         b=2\n" +
+				"INFO database: connecting to c=3\n" +
+				"INFO app:            d=4\n" +
+				"INFO app: done\n",
+		},
+		{
+			name:      "adaptive",
+			alignment: Alignment{Adaptive: true},
+			expected: "INFO app: starting a=1\n" +
+				"INFO      ok       b=2\n" +
+				"INFO database: connecting to c=3\n" +
+				"INFO app:                    d=4\n" +
+				"INFO app:      done\n",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			l := NewWithOptions(&buf, Options{Alignment: c.alignment})
+			for _, e := range entries {
+				l.WithPrefix(e.prefix).Info(e.msg, e.kvs...)
+			}
+			assert.Equal(t, c.expected, buf.String())
+		})
+	}
+}
+
+func TestAlignmentWidth(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWithOptions(&buf, Options{Alignment: Alignment{MessageWidth: 6}})
+	l.SetColorProfile(colorprofile.ANSI)
+	st := MinimalStyles()
+	st.Message = st.Message.Bold(true)
+	l.SetStyles(st)
+
+	l.Info("猫", "a", 1)
+	l.Info("abc", "a", 1)
+	assert.Equal(t, "INFO \x1b[1m猫\x1b[m     a=1\nINFO \x1b[1mabc\x1b[m    a=1\n", buf.String())
+}
+
+func TestTerminalLayout(t *testing.T) {
+	_, _, line, _ := runtime.Caller(0)
+	info := func(l *Logger, msg string, kvs ...any) { l.Info(msg, kvs...) }
+	caller := fmt.Sprintf("<log/layout_test.go:%d>", line+1)
+	right := caller + " 0002/01/01 00:00:00"
+	pad := func(left string) string {
+		return left + strings.Repeat(" ", 60-len(left)-len(right)) + right + "\n"
+	}
+	cases := []struct {
+		name     string
+		width    int
+		msg      string
+		kvs      []any
+		expected string
+	}{
+		{
+			name:     "not a terminal",
+			width:    0,
+			msg:      "msg",
+			kvs:      []any{"a", 1},
+			expected: "0002/01/01 00:00:00 INFO " + caller + " msg a=1\n",
+		},
+		{
+			name:     "right aligned",
+			width:    60,
+			msg:      "msg",
+			kvs:      []any{"a", 1},
+			expected: pad("INFO msg a=1"),
+		},
+		{
+			name:  "wrapped",
+			width: 60,
+			msg:   "msg",
+			kvs:   []any{"a", 1, "first", "value", "second", "value", "third", "value", "fourth", "value"},
+			expected: pad("INFO msg a=1") +
+				"     first=value second=value third=value fourth=value\n",
+		},
+		{
+			name:  "too narrow",
+			width: 20,
+			msg:   "msg",
+			kvs:   []any{"first", "value", "second", "value"},
+			expected: "INFO msg " + right + "\n" +
+				"     first=value\n" +
+				"     second=value\n",
+		},
+		{
+			name:  "multiline",
+			width: 60,
+			msg:   "msg",
+			kvs:   []any{"multi", "a\nb", "a", 1},
+			expected: pad("INFO msg a=1") +
+				"  multi=\n" +
+				"  │ a\n" +
+				"  │ b\n",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			l := NewWithOptions(&buf, Options{
+				Layout:          TerminalLayout,
+				ReportTimestamp: true,
+				ReportCaller:    true,
+				TimeFunction:    _zeroTime,
+			})
+			l.state.termWidth = func(io.Writer) int { return c.width }
+			info(l, c.msg, c.kvs...)
+			assert.Equal(t, c.expected, buf.String())
+		})
+	}
+}
+
+func TestCompact(t *testing.T) {
+	var buf bytes.Buffer
+	ts := time.Date(2026, 10, 17, 10, 1, 2, 0, time.UTC)
+	l := NewWithOptions(&buf, Options{
+		Compact:         true,
+		ReportTimestamp: true,
+		TimeFormat:      time.TimeOnly,
+		TimeFunction:    func(time.Time) time.Time { return ts },
+	})
+	app := l.WithPrefix("myapp")
+	db := l.WithPrefix("db")
+
+	app.Info("starting")
+	app.Info("loading config", "path", "/etc/app")
+	// The prefix changes.
+	db.Info("connecting")
+	// The timestamp changes.
+	ts = ts.Add(time.Second)
+	db.Warn("slow query")
+	// Both change.
+	ts = ts.Add(time.Second)
+	app.Info("ready")
+	l.Info("no prefix")
+
+	expected := "10:01:02 myapp:\n" +
+		"         INFO        starting\n" +
+		"         INFO        loading config path=/etc/app\n" +
+		"10:01:02 db:\n" +
+		"         INFO     connecting\n" +
+		"10:01:03 db:\n" +
+		"         WARN     slow query\n" +
+		"10:01:04 myapp:\n" +
+		"         INFO        ready\n" +
+		"10:01:04\n" +
+		"         INFO no prefix\n"
+	assert.Equal(t, expected, buf.String())
+}
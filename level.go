@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"strconv"
 	"strings"
 )
 
@@ -63,3 +64,24 @@ func ParseLevel(level string) (Level, error) {
 		return 0, fmt.Errorf("%w: %q", ErrInvalidLevel, level)
 	}
 }
+
+// parseEncodedLevel parses a level name as encoded by LowercaseLevelEncoder,
+// e.g. "warn" or "info+2", or a numeric level.
+func parseEncodedLevel(name string) (Level, error) {
+	if n, err := strconv.Atoi(name); err == nil {
+		return Level(n), nil
+	}
+	base, offset := name, 0
+	if i := strings.IndexAny(name, "+-"); i > 0 {
+		n, err := strconv.Atoi(name[i:])
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidLevel, name)
+		}
+		base, offset = name[:i], n
+	}
+	level, err := ParseLevel(base)
+	if err != nil {
+		return 0, err
+	}
+	return level + Level(offset), nil
+}
@@ -0,0 +1,164 @@
+package log
+
+import (
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/colorprofile"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Limits caps the size of log entries, e.g. to protect log collectors from
+// huge values. Zero values disable the limits.
+type Limits struct {
+	// MaxValueLength is the maximum length of values in bytes. Longer values
+	// are truncated, and a "…(+N bytes)" marker is appended. The length of
+	// values that aren't strings is the length of their string
+	// representation.
+	MaxValueLength int
+	// MaxFields is the maximum number of fields per entry, including the
+	// logger fields. Extra fields are dropped, and their count is reported
+	// with the DroppedFieldsKey field.
+	MaxFields int
+	// MaxEntrySize is the maximum size of a formatted entry in bytes. Fields
+	// are dropped from the end of entries that don't fit, and their count is
+	// reported with the DroppedFieldsKey field. If the entry still doesn't
+	// fit, its message is truncated.
+	MaxEntrySize int
+}
+
+// truncationMarker returns the marker appended to values truncated by n
+// bytes.
+func truncationMarker(n int) string {
+	return fmt.Sprintf("…(+%d bytes)", n)
+}
+
+// truncateString truncates s to at most n bytes, without splitting runes,
+// and appends a truncation marker.
+func truncateString(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	n = max(n, 0)
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + truncationMarker(len(s)-n)
+}
+
+// limitFields applies the MaxFields and MaxValueLength limits to the fields
+// of kvs, starting at index start. Built-in fields before start are left
+// as-is.
+func (l *Logger) limitFields(kvs []any, start int) []any {
+	if n := l.limits.MaxFields; n > 0 && (len(kvs)-start)/2 > n {
+		dropped := (len(kvs)-start)/2 - n
+		kvs = append(kvs[:start+2*n], l.keys.DroppedFields, dropped)
+	}
+	if n := l.limits.MaxValueLength; n > 0 {
+		for i := start + 1; i < len(kvs); i += 2 {
+			kvs[i] = l.truncateValue(kvs[i], n)
+		}
+	}
+	return kvs
+}
+
+// truncateValue returns v, or its truncated string representation if it's
+// longer than n bytes.
+func (l *Logger) truncateValue(v any, n int) any {
+	var s string
+	switch v := v.(type) {
+	case nil, bool, int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64, uintptr,
+		float32, float64, complex64, complex128,
+		time.Duration, time.Time:
+		return v
+	case string:
+		s = v
+	case []byte:
+		if l.formatter == TextFormatter || l.formatter == HTMLFormatter {
+			// The TextFormatter caps hex dumps itself, see hexLimit.
+			return v
+		}
+		s = l.encodeBytes(v)
+	case error, fmt.Stringer:
+		s = fmt.Sprint(v)
+	default:
+		s = fmt.Sprintf("%+v", v)
+	}
+	if len(s) <= n {
+		return v
+	}
+	return truncateString(s, n)
+}
+
+// hexLimit returns the maximum number of bytes shown in hex dumps.
+func (l *Logger) hexLimit() int {
+	limit := l.hexDumpLimit
+	if limit == 0 {
+		limit = DefaultHexDumpLimit
+	}
+	if n := l.limits.MaxValueLength; n > 0 && (limit < 0 || n < limit) {
+		limit = n
+	}
+	return limit
+}
+
+// entrySize returns the size of the entry in the buffer once written. Color
+// profiles other than NoTTY only downsample colors, so the buffer size is an
+// upper bound. Binary entries are written as-is.
+func (l *Logger) entrySize() int {
+	if l.w.Profile <= colorprofile.NoTTY && l.formatter != CBORFormatter {
+		return len(ansi.Strip(l.b.String()))
+	}
+	return l.b.Len()
+}
+
+// fitEntry re-formats the entry in the buffer until it fits in MaxEntrySize
+// bytes, dropping fields from the end, then truncating the message. The
+// fields of kvs start at index start, and state is the text state before the
+// entry was first formatted.
+func (l *Logger) fitEntry(level Level, kvs []any, start int, state textEntryState) {
+	size := l.limits.MaxEntrySize
+	if size <= 0 || l.entrySize() <= size {
+		return
+	}
+
+	// Each attempt formats the entry from the state before the first one, so
+	// that the discarded attempts don't affect the entries that follow.
+	reformat := func() {
+		l.state.setEntryState(state)
+		l.b.Reset()
+		l.format(level, kvs...)
+	}
+
+	builtins := kvs[:start:start]
+	fields := kvs[start:]
+	dropped := 0
+	if n := len(fields); n >= 2 && fields[n-2] == l.keys.DroppedFields {
+		// Fields were already dropped by MaxFields.
+		dropped, _ = fields[n-1].(int)
+		fields = fields[:n-2]
+	}
+	for len(fields) >= 2 && l.entrySize() > size {
+		fields = fields[:len(fields)-2]
+		dropped++
+		kvs = append(append(builtins, fields...), l.keys.DroppedFields, dropped)
+		reformat()
+	}
+
+	// Truncate the message by what's left over, until it fits.
+	for i := 0; i < start; i += 2 {
+		if kvs[i] != l.keys.Message {
+			continue
+		}
+		msg := fmt.Sprint(kvs[i+1])
+		n := len(msg)
+		for l.entrySize() > size && n > 0 {
+			n = max(n-(l.entrySize()-size), 0)
+			kvs[i+1] = truncateString(msg, n)
+			reformat()
+		}
+		break
+	}
+}
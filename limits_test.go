@@ -0,0 +1,155 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTruncateString(t *testing.T) {
+	assert.Equal(t, "hello", truncateString("hello", 5))
+	assert.Equal(t, "hel…(+2 bytes)", truncateString("hello", 3))
+	assert.Equal(t, "…(+5 bytes)", truncateString("hello", 0))
+	// Runes aren't split.
+	assert.Equal(t, "h…(+5 bytes)", truncateString("héllo", 2))
+}
+
+func TestMaxValueLength(t *testing.T) {
+	cases := []struct {
+		name      string
+		formatter Formatter
+		expected  string
+	}{
+		{
+			name:      "text",
+			formatter: TextFormatter,
+			expected:  "INFO msg s=abcd…(+6 bytes) n=1234567890 err=\"boom…(+7 bytes)\" b=[]\n",
+		},
+		{
+			name:      "json",
+			formatter: JSONFormatter,
+			expected:  "{\"level\":\"info\",\"msg\":\"msg\",\"s\":\"abcd…(+6 bytes)\",\"n\":1234567890,\"err\":\"boom…(+7 bytes)\",\"b\":\"AQID…(+4 bytes)\"}\n",
+		},
+		{
+			name:      "logfmt",
+			formatter: LogfmtFormatter,
+			expected:  "level=info msg=msg s=\"abcd…(+6 bytes)\" n=1234567890 err=\"boom…(+7 bytes)\" b=\"AQID…(+4 bytes)\"\n",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			l := NewWithOptions(&buf, Options{
+				Formatter: c.formatter,
+				Limits:    Limits{MaxValueLength: 4},
+			})
+			l.Info("msg",
+				"s", "abcdefghij",
+				"n", 1234567890,
+				"err", errors.New("boom boom!!"),
+				"b", []byte{1, 2, 3, 4, 5, 6},
+			)
+			if c.formatter == TextFormatter {
+				// Hex dumps are capped instead.
+				assert.Contains(t, buf.String(), "│ …(+2 bytes)\n")
+				return
+			}
+			assert.Equal(t, c.expected, buf.String())
+		})
+	}
+}
+
+func TestMaxFields(t *testing.T) {
+	cases := []struct {
+		name      string
+		formatter Formatter
+		expected  string
+	}{
+		{"text", TextFormatter, "INFO msg a=1 b=2 dropped_fields=2\n"},
+		{"json", JSONFormatter, "{\"level\":\"info\",\"msg\":\"msg\",\"a\":1,\"b\":2,\"dropped_fields\":2}\n"},
+		{"logfmt", LogfmtFormatter, "level=info msg=msg a=1 b=2 dropped_fields=2\n"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			l := NewWithOptions(&buf, Options{
+				Formatter: c.formatter,
+				Limits:    Limits{MaxFields: 2},
+			}).With("a", 1)
+			l.Info("msg", "b", 2, "c", 3, "d", 4)
+			assert.Equal(t, c.expected, buf.String())
+		})
+	}
+}
+
+func TestMaxEntrySize(t *testing.T) {
+	for _, f := range []Formatter{TextFormatter, JSONFormatter, LogfmtFormatter} {
+		var buf bytes.Buffer
+		l := NewWithOptions(&buf, Options{
+			Formatter: f,
+			Limits:    Limits{MaxEntrySize: 80},
+		})
+
+		l.Info("msg", "a", 1, "big", strings.Repeat("x", 100), "c", 3)
+		out := buf.String()
+		assert.LessOrEqual(t, len(out), 80, out)
+		assert.Contains(t, out, "a")
+		assert.Contains(t, out, "dropped_fields")
+		assert.NotContains(t, out, "xxx")
+		if f == JSONFormatter {
+			assert.True(t, json.Valid([]byte(out)), out)
+			var m map[string]any
+			require.NoError(t, json.Unmarshal([]byte(out), &m))
+			assert.Equal(t, float64(2), m["dropped_fields"])
+		}
+
+		buf.Reset()
+		l.Info(strings.Repeat("m", 200), "a", 1)
+		out = buf.String()
+		assert.LessOrEqual(t, len(out), 80, out)
+		assert.Contains(t, out, "bytes)")
+		if f == JSONFormatter {
+			assert.True(t, json.Valid([]byte(out)), out)
+		}
+	}
+}
+
+func TestMaxEntrySizeCompact(t *testing.T) {
+	var buf bytes.Buffer
+	ts := time.Date(2026, 10, 17, 10, 1, 2, 0, time.UTC)
+	l := NewWithOptions(&buf, Options{
+		Compact:         true,
+		ReportTimestamp: true,
+		TimeFormat:      time.TimeOnly,
+		TimeFunction:    func(time.Time) time.Time { return ts },
+		Prefix:          "app",
+		Limits:          Limits{MaxEntrySize: 60},
+	})
+
+	l.Info(strings.Repeat("m", 100))
+	l.Info("ok")
+
+	lines := strings.Split(buf.String(), "\n")
+	require.Len(t, lines, 4)
+	assert.Equal(t, "10:01:02 app:", lines[0])
+	assert.True(t, strings.HasPrefix(lines[1], "         INFO      mmm"), lines[1])
+	assert.Equal(t, "         INFO      ok", lines[2])
+}
+
+func TestMaxEntrySizeAlignment(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWithOptions(&buf, Options{
+		Alignment: Alignment{Adaptive: true},
+		Limits:    Limits{MaxEntrySize: 40},
+	})
+
+	l.Info(strings.Repeat("m", 100))
+	first, _, _ := strings.Cut(buf.String(), "\n")
+	assert.Equal(t, stringWidth(strings.TrimPrefix(first, "INFO ")), l.state.messageWidth)
+}
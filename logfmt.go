@@ -13,14 +13,21 @@ func (l *Logger) logfmtFormatter(keyvals ...any) {
 
 	for i := 0; i < len(keyvals); i += 2 {
 		switch keyvals[i] {
-		case TimestampKey:
+		case l.keys.Timestamp:
 			if t, ok := keyvals[i+1].(time.Time); ok {
-				keyvals[i+1] = t.Format(l.timeFormat)
+				keyvals[i+1] = l.encodeTime(t)
+			}
+		case l.keys.Level:
+			if level, ok := keyvals[i+1].(Level); ok {
+				keyvals[i+1] = l.encodeLevel(level)
 			}
 		default:
 			if key := fmt.Sprint(keyvals[i]); key != "" {
 				keyvals[i] = key
 			}
+			if b, ok := keyvals[i+1].([]byte); ok {
+				keyvals[i+1] = l.encodeBytes(b)
+			}
 		}
 		err := e.EncodeKeyval(keyvals[i], keyvals[i+1])
 		if err != nil && errors.Is(err, logfmt.ErrUnsupportedValueType) {
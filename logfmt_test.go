@@ -35,6 +35,13 @@ func TestLogfmt(t *testing.T) {
 			kvs:      nil,
 			f:        l.Debug,
 		},
+		{
+			name:     "multiline message",
+			expected: "level=info msg=\"line 1\\nline \\\"2\\\"\" foo=bar\n",
+			msg:      "line 1\nline \"2\"",
+			kvs:      []any{"foo", "bar"},
+			f:        l.Info,
+		},
 		{
 			name:     "message with keyvals",
 			expected: "level=info msg=info foo=bar\n",
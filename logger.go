@@ -33,17 +33,49 @@ type Logger struct {
 	prefix          string
 	timeFunc        TimeFunction
 	timeFormat      string
+	timeEncoder     TimeEncoder
+	timestampMode   TimestampMode
+	alignment       Alignment
+	layout          Layout
+	compact         bool
+	pretty          PrettyValues
+	hyperlinks      Hyperlinks
+	bytesEncoding   BytesEncoding
+	hexDumpLimit    int
+	limits          Limits
+	splitMessages   bool
+	json            JSONOptions
+	csv             CSVOptions
+	html            HTMLOptions
+	recorder        io.Writer
+	levelEncoder    LevelEncoder
 	callerOffset    int
 	callerFormatter CallerFormatter
 	formatter       Formatter
+	keys            KeyNames
 
-	reportCaller    bool
-	reportTimestamp bool
+	reportCaller     bool
+	reportStackTrace bool
+	stackTraceLevel  Level
+	stack            bool
+	reportTimestamp  bool
 
 	fields []any
+	hooks  []Hook
+	sinks  []*Logger
 
 	helpers *sync.Map
 	styles  *Styles
+	state   *textState
+
+	// levelStyles caches the effective styles of the levels with overrides
+	// in levelStylesOf.
+	levelStyles   map[Level]*Styles
+	levelStylesOf *Styles
+
+	// detectStyles are the default styles, until the terminal background is
+	// detected.
+	detectStyles *Styles
 }
 
 // Logf logs a message with formatting.
@@ -53,63 +85,167 @@ func (l *Logger) Logf(level Level, format string, args ...any) {
 
 // Log logs the given message with the given keyvals for the given level.
 func (l *Logger) Log(level Level, msg any, keyvals ...any) {
-	if atomic.LoadUint32(&l.isDiscard) != 0 {
+	// check if the level is allowed, by the logger or any of its sinks
+	if !l.wantsLevel(level) {
 		return
 	}
 
-	// check if the level is allowed
-	if atomic.LoadInt64(&l.level) > int64(level) {
+	frames := []runtime.Frame{{}}
+	if stack := l.wantsStack(level); stack || l.wantsCaller() {
+		// Skip log.log, the caller, and any offset added.
+		frames = l.callerFrames(l.callerOffset+2, stack)
+	}
+	l.handle(level, time.Now(), frames, msg, keyvals...)
+}
+
+// Replay writes an entry decoded from the output of a logger, e.g. by a
+// CBORDecoder, with this logger's formatter. The timestamp, level, caller,
+// prefix, and message are read from the logger keys. Levels can be names,
+// like "warn" or "info+2", or numbers. Entries below the logger level are
+// skipped, and the logger fields, prefix, and hooks aren't applied.
+func (l *Logger) Replay(keyvals ...any) {
+	if atomic.LoadUint32(&l.isDiscard) != 0 {
 		return
 	}
 
-	var frame runtime.Frame
-	if l.reportCaller {
-		// Skip log.log, the caller, and any offset added.
-		frames := l.frames(l.callerOffset + 2)
-		for {
-			f, more := frames.Next()
-			_, helper := l.helpers.Load(f.Function)
-			if !helper || !more {
-				// Found a frame that wasn't a helper function.
-				// Or we ran out of frames to check.
-				frame = f
-				break
+	level := noLevel
+	var ts time.Time
+	var caller, prefix, msg string
+	var fields []any
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		key, value := keyvals[i], keyvals[i+1]
+		switch key {
+		case l.keys.Timestamp:
+			if t, ok := value.(time.Time); ok && ts.IsZero() {
+				ts = t
+				continue
+			}
+		case l.keys.Level:
+			if lvl, ok := replayLevel(value); ok && level == noLevel {
+				level = lvl
+				continue
+			}
+		case l.keys.Caller:
+			if s, ok := value.(string); ok && caller == "" {
+				caller = s
+				continue
+			}
+		case l.keys.Prefix:
+			if s, ok := value.(string); ok && prefix == "" {
+				prefix = s
+				continue
+			}
+		case l.keys.Message:
+			if value != nil && msg == "" {
+				msg = fmt.Sprint(value)
+				continue
 			}
 		}
+		fields = append(fields, key, value)
+	}
+	if level != noLevel && atomic.LoadInt64(&l.level) > int64(level) {
+		return
+	}
+
+	var kvs []any
+	if !ts.IsZero() {
+		kvs = append(kvs, l.keys.Timestamp, ts)
 	}
-	l.handle(level, l.timeFunc(time.Now()), []runtime.Frame{frame}, msg, keyvals...)
+	if l.reportsLevel(level) {
+		kvs = append(kvs, l.keys.Level, level)
+	}
+	if caller != "" {
+		kvs = append(kvs, l.keys.Caller, caller)
+	}
+	if prefix != "" {
+		kvs = append(kvs, l.keys.Prefix, prefix)
+	}
+	if msg != "" {
+		kvs = append(kvs, l.keys.Message, msg)
+	}
+	start := len(kvs)
+	l.write(level, append(kvs, fields...), start)
+}
+
+// replayLevel returns the level of a decoded level value.
+func replayLevel(v any) (Level, bool) {
+	switch v := v.(type) {
+	case Level:
+		return v, true
+	case int64:
+		return Level(v), true
+	case int:
+		return Level(v), true
+	case float64:
+		return Level(v), true
+	case string:
+		if level, err := parseEncodedLevel(v); err == nil {
+			return level, true
+		}
+	}
+	return 0, false
 }
 
+// handle writes an entry, and forwards it to the logger hooks and sinks. The
+// logger time function is applied to ts.
 func (l *Logger) handle(level Level, ts time.Time, frames []runtime.Frame, msg any, keyvals ...any) {
+	if l.splitMessages && msg != nil {
+		if m := fmt.Sprint(msg); strings.Contains(m, "\n") {
+			// A trailing newline doesn't start another entry.
+			for _, line := range strings.Split(strings.TrimSuffix(m, "\n"), "\n") {
+				l.handle(level, ts, frames, line, keyvals...)
+			}
+			return
+		}
+	}
+
+	l.fire(level, ts, frames, msg, keyvals)
+	if !l.enabled(level) {
+		return
+	}
+
 	var kvs []any
-	if l.reportTimestamp && !ts.IsZero() {
-		kvs = append(kvs, TimestampKey, ts)
+	if ts = l.timeFunc(ts); l.reportTimestamp && !ts.IsZero() {
+		kvs = append(kvs, l.keys.Timestamp, ts)
 	}
 
-	_, ok := l.styles.Levels[level]
-	if ok {
-		kvs = append(kvs, LevelKey, level)
+	if l.reportsLevel(level) {
+		kvs = append(kvs, l.keys.Level, level)
 	}
 
 	if l.reportCaller && len(frames) > 0 && frames[0].PC != 0 {
 		file, line, fn := l.location(frames)
 		if file != "" {
 			caller := l.callerFormatter(file, line, fn)
-			kvs = append(kvs, CallerKey, caller)
+			if url := l.callerURL(file, line, fn); url != "" {
+				kvs = append(kvs, l.keys.Caller, hyperlink{caller, url})
+			} else {
+				kvs = append(kvs, l.keys.Caller, caller)
+			}
 		}
 	}
 
 	if l.prefix != "" {
-		kvs = append(kvs, PrefixKey, l.prefix)
+		kvs = append(kvs, l.keys.Prefix, l.prefix)
 	}
 
 	if msg != nil {
 		if m := fmt.Sprint(msg); m != "" {
-			kvs = append(kvs, MessageKey, m)
+			if t, ok := msg.(*messageTemplate); ok {
+				// Keep the template, so that the TextFormatter can style
+				// the values.
+				kvs = append(kvs, l.keys.Message, t)
+				if l.formatter != TextFormatter && l.formatter != HTMLFormatter {
+					kvs = append(kvs, l.keys.MessageTemplate, t.text)
+				}
+			} else {
+				kvs = append(kvs, l.keys.Message, m)
+			}
 		}
 	}
 
 	// append logger fields
+	start := len(kvs)
 	kvs = append(kvs, l.fields...)
 	if len(l.fields)%2 != 0 {
 		kvs = append(kvs, ErrMissingValue)
@@ -121,21 +257,60 @@ func (l *Logger) handle(level Level, ts time.Time, frames []runtime.Frame, msg a
 		kvs = append(kvs, ErrMissingValue)
 	}
 
+	if l.reportsStack(level) && len(frames) > 0 && frames[0].PC != 0 {
+		kvs = append(kvs, l.keys.Stack, stackTrace(frames))
+	}
+
+	l.write(level, kvs, start)
+}
+
+// reportsLevel reports whether entries at the given level include the level.
+// The text formatters only report levels without a style, like custom levels,
+// when there's a level encoder to encode them.
+func (l *Logger) reportsLevel(level Level) bool {
+	if level == noLevel {
+		return false
+	}
+	if l.formatter != TextFormatter && l.formatter != HTMLFormatter {
+		return true
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	_, ok := l.styles.Levels[level]
+	return ok || l.levelEncoder != nil
+}
+
+// write formats the entry and writes it to the output. The fields of kvs
+// start at index start.
+func (l *Logger) write(level Level, kvs []any, start int) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	switch l.formatter {
-	case LogfmtFormatter:
-		l.logfmtFormatter(kvs...)
-	case JSONFormatter:
-		l.jsonFormatter(kvs...)
-	case TextFormatter:
-		fallthrough
-	default:
-		l.textFormatter(kvs...)
+	l.detectBackground()
+	kvs = l.limitFields(kvs, start)
+	var state textEntryState
+	if l.limits.MaxEntrySize > 0 {
+		state = l.state.entryState()
+	}
+	l.format(level, kvs...)
+	l.fitEntry(level, kvs, start, state)
+	l.writeCSVHeader()
+	l.writeHTMLHeader()
+
+	// The recording keeps the styles of the entry, whatever the color profile
+	// of the output. Binary entries aren't recorded.
+	if l.recorder != nil && l.formatter != CBORFormatter {
+		_, _ = l.recorder.Write(l.b.Bytes())
+	}
+
+	// Binary entries bypass the color profile, which would strip bytes that
+	// look like escape sequences.
+	var w io.Writer = &l.w
+	if l.formatter == CBORFormatter {
+		w = l.w.Forward
 	}
 
 	// WriteTo will reset the buffer
-	if _, err := l.b.WriteTo(&l.w); err != nil {
+	if _, err := l.b.WriteTo(w); err != nil {
 		if errors.Is(err, io.ErrShortWrite) {
 			// Reset the buffer even if the lengths don't match up. If we're
 			// using colorprofile's Writer, it will strip the ansi sequences based on
@@ -145,6 +320,30 @@ func (l *Logger) handle(level Level, ts time.Time, frames []runtime.Frame, msg a
 	}
 }
 
+// format formats the entry into the buffer.
+func (l *Logger) format(level Level, kvs ...any) {
+	switch l.formatter {
+	case LogfmtFormatter:
+		l.logfmtFormatter(kvs...)
+	case JSONFormatter:
+		l.jsonFormatter(kvs...)
+	case YAMLFormatter:
+		l.yamlFormatter(kvs...)
+	case CSVFormatter:
+		l.csvFormatter(',', kvs...)
+	case TSVFormatter:
+		l.csvFormatter('\t', kvs...)
+	case CBORFormatter:
+		l.cborFormatter(kvs...)
+	case HTMLFormatter:
+		l.htmlFormatter(level, kvs...)
+	case TextFormatter:
+		fallthrough
+	default:
+		l.textFormatter(level, kvs...)
+	}
+}
+
 // Helper marks the calling function as a helper
 // and skips it for source location information.
 // It's the equivalent of testing.TB.Helper().
@@ -317,7 +516,8 @@ func (l *Logger) SetCallerOffset(offset int) {
 	l.callerOffset = offset
 }
 
-// SetStyles sets the logger styles for the TextFormatter.
+// SetStyles sets the logger styles for the TextFormatter. The level overrides
+// of s are applied once, so changes to them after s is set don't take effect.
 func (l *Logger) SetStyles(s *Styles) {
 	if s == nil {
 		s = DefaultStyles()
@@ -339,10 +539,29 @@ func (l *Logger) With(keyvals ...any) *Logger {
 	sl.helpers = &sync.Map{}
 	sl.fields = append(make([]any, 0, len(l.fields)+len(keyvals)), l.fields...)
 	sl.fields = append(sl.fields, keyvals...)
+	if sl.detectStyles != nil && sl.styles == sl.detectStyles {
+		sl.detectStyles = &st
+	}
 	sl.styles = &st
 	return &sl
 }
 
+// WithOptions returns a new logger with the given options applied.
+func (l *Logger) WithOptions(opts ...LoggerOption) *Logger {
+	sl := l.With()
+	sl.SetOptions(opts...)
+	return sl
+}
+
+// SetOptions applies the given options to the logger.
+func (l *Logger) SetOptions(opts ...LoggerOption) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, opt := range opts {
+		opt(l)
+	}
+}
+
 // WithPrefix returns a new logger with the given prefix.
 func (l *Logger) WithPrefix(prefix string) *Logger {
 	sl := l.With()
@@ -411,3 +630,38 @@ func (l *Logger) Fatalf(format string, args ...any) {
 func (l *Logger) Printf(format string, args ...any) {
 	l.Log(noLevel, fmt.Sprintf(format, args...))
 }
+
+// Logt logs a message template with the given keyvals for the given level.
+// Placeholders like "{user}" are replaced with the value of the field with
+// the same key, and the template is kept in the MessageTemplateKey field,
+// except in the TextFormatter and HTMLFormatter. Use "{{" and "}}" for
+// literal braces.
+func (l *Logger) Logt(level Level, template string, keyvals ...any) {
+	l.Log(level, l.template(template, keyvals), keyvals...)
+}
+
+// Debugt prints a debug message template. See Logt.
+func (l *Logger) Debugt(template string, keyvals ...any) {
+	l.Log(DebugLevel, l.template(template, keyvals), keyvals...)
+}
+
+// Infot prints an info message template. See Logt.
+func (l *Logger) Infot(template string, keyvals ...any) {
+	l.Log(InfoLevel, l.template(template, keyvals), keyvals...)
+}
+
+// Warnt prints a warning message template. See Logt.
+func (l *Logger) Warnt(template string, keyvals ...any) {
+	l.Log(WarnLevel, l.template(template, keyvals), keyvals...)
+}
+
+// Errort prints an error message template. See Logt.
+func (l *Logger) Errort(template string, keyvals ...any) {
+	l.Log(ErrorLevel, l.template(template, keyvals), keyvals...)
+}
+
+// Fatalt prints a fatal message template and exits. See Logt.
+func (l *Logger) Fatalt(template string, keyvals ...any) {
+	l.Log(FatalLevel, l.template(template, keyvals), keyvals...)
+	os.Exit(1)
+}
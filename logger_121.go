@@ -6,7 +6,6 @@ import (
 	"context"
 	"log/slog"
 	"runtime"
-	"sync/atomic"
 )
 
 // type aliases for slog.
@@ -20,14 +19,17 @@ var slogAnyValue = slog.AnyValue //nolint:unused
 
 const slogKindGroup = slog.KindGroup
 
-// Enabled reports whether the logger is enabled for the given level.
+// Enabled reports whether the logger, or any of its sinks, is enabled for the
+// given level.
 //
 // Implements slog.Handler.
 func (l *Logger) Enabled(_ context.Context, level slog.Level) bool {
-	return atomic.LoadInt64(&l.level) <= int64(level)
+	return l.wantsLevel(Level(level))
 }
 
 // Handle handles the Record. It will only be called if Enabled returns true.
+// Records only keep the program counter of their caller, so the reported
+// stack trace of their entries is the caller frame alone.
 //
 // Implements slog.Handler.
 func (l *Logger) Handle(ctx context.Context, record slog.Record) error {
@@ -43,7 +45,7 @@ func (l *Logger) Handle(ctx context.Context, record slog.Record) error {
 	// Get the caller frame using the record's PC.
 	frames := runtime.CallersFrames([]uintptr{record.PC})
 	frame, _ := frames.Next()
-	l.handle(Level(record.Level), l.timeFunc(record.Time), []runtime.Frame{frame}, record.Message, fields...)
+	l.handle(Level(record.Level), record.Time, []runtime.Frame{frame}, record.Message, fields...)
 	return nil
 }
 
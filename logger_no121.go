@@ -5,7 +5,6 @@ package log
 import (
 	"context"
 	"runtime"
-	"sync/atomic"
 
 	"golang.org/x/exp/slog"
 )
@@ -21,14 +20,17 @@ var slogAnyValue = slog.AnyValue
 
 const slogKindGroup = slog.KindGroup
 
-// Enabled reports whether the logger is enabled for the given level.
+// Enabled reports whether the logger, or any of its sinks, is enabled for the
+// given level.
 //
 // Implements slog.Handler.
 func (l *Logger) Enabled(_ context.Context, level slog.Level) bool {
-	return atomic.LoadInt64(&l.level) <= int64(level)
+	return l.wantsLevel(Level(level))
 }
 
 // Handle handles the Record. It will only be called if Enabled returns true.
+// Records only keep the program counter of their caller, so the reported
+// stack trace of their entries is the caller frame alone.
 //
 // Implements slog.Handler.
 func (l *Logger) Handle(_ context.Context, record slog.Record) error {
@@ -40,7 +42,7 @@ func (l *Logger) Handle(_ context.Context, record slog.Record) error {
 	// Get the caller frame using the record's PC.
 	frames := runtime.CallersFrames([]uintptr{record.PC})
 	frame, _ := frames.Next()
-	l.handle(Level(record.Level), l.timeFunc(record.Time), []runtime.Frame{frame}, record.Message, fields...)
+	l.handle(Level(record.Level), record.Time, []runtime.Frame{frame}, record.Message, fields...)
 	return nil
 }
 
@@ -2,7 +2,11 @@ package log
 
 import (
 	"fmt"
+	"io"
+	"sync/atomic"
 	"time"
+
+	"github.com/charmbracelet/colorprofile"
 )
 
 // DefaultTimeFormat is the default time format.
@@ -42,14 +46,71 @@ type Options struct {
 	TimeFunction TimeFunction
 	// TimeFormat is the time format for the logger. The default is "2006/01/02 15:04:05".
 	TimeFormat string
+	// TimeEncoder is the time encoder for the logger. The default is to use
+	// TimeFormat for the TextFormatter and HTMLFormatter, CBOR date/times for
+	// the CBORFormatter, and RFC3339Nano in UTC for the other formatters.
+	TimeEncoder TimeEncoder
 	// Level is the level for the logger. The default is InfoLevel.
 	Level Level
+	// TimestampMode is the timestamp mode for the TextFormatter. The default
+	// is WallClockTimestamp.
+	TimestampMode TimestampMode
+	// Alignment controls how the TextFormatter aligns prefixes and messages.
+	// The default is no alignment.
+	Alignment Alignment
+	// Layout is the layout mode of the TextFormatter. The default is
+	// InlineLayout.
+	Layout Layout
+	// Compact is whether the TextFormatter elides repeated timestamps and
+	// prefixes. A group header with the timestamp and prefix is written when
+	// either changes, and the entries below it have them blanked out. The
+	// default is false.
+	Compact bool
+	// PrettyValues controls how the TextFormatter renders structs, maps,
+	// slices, and arrays. The default is to render them on a single line.
+	PrettyValues PrettyValues
+	// Hyperlinks controls the OSC 8 hyperlinks of the TextFormatter. The
+	// default is no hyperlinks.
+	Hyperlinks Hyperlinks
+	// BytesEncoding is the encoding of []byte values in the JSONFormatter
+	// and LogfmtFormatter. The default is Base64Bytes.
+	BytesEncoding BytesEncoding
+	// HexDumpLimit is the maximum number of bytes shown in the hex dumps of
+	// []byte values in the TextFormatter. Negative values show all bytes.
+	// The default is DefaultHexDumpLimit.
+	HexDumpLimit int
+	// Limits caps the size of entries. The default is no limits.
+	Limits Limits
+	// SplitMessages is whether multi-line messages are split into separate
+	// entries, one per line, with the same fields. The default is false.
+	SplitMessages bool
+	// JSON configures the JSONFormatter.
+	JSON JSONOptions
+	// CSV configures the CSVFormatter and TSVFormatter.
+	CSV CSVOptions
+	// HTML configures the HTMLFormatter.
+	HTML HTMLOptions
+	// Recorder receives a copy of the output, with its styles in full color
+	// whatever the color profile of the output, e.g. an AsciicastWriter.
+	// Wrap it in a colorprofile.Writer to record with a given profile. The
+	// output of the CBORFormatter isn't recorded.
+	Recorder io.Writer
+	// LevelEncoder is the level encoder for the logger. The default is to use
+	// the level styles for the TextFormatter and HTMLFormatter, and
+	// LowercaseLevelEncoder for the other formatters.
+	LevelEncoder LevelEncoder
 	// Prefix is the prefix for the logger. The default is no prefix.
 	Prefix string
 	// ReportTimestamp is whether the logger should report the timestamp. The default is false.
 	ReportTimestamp bool
 	// ReportCaller is whether the logger should report the caller location. The default is false.
 	ReportCaller bool
+	// ReportStackTrace is whether the logger should report the stack trace of
+	// entries at or above StackTraceLevel. The default is false.
+	ReportStackTrace bool
+	// StackTraceLevel is the minimum level of the entries with a stack trace,
+	// when ReportStackTrace is set. The default, when it's nil, is ErrorLevel.
+	StackTraceLevel *Level
 	// CallerFormatter is the caller format for the logger. The default is ShortCallerFormatter.
 	CallerFormatter CallerFormatter
 	// CallerOffset is the caller format for the logger. The default is 0.
@@ -58,4 +119,285 @@ type Options struct {
 	Fields []any
 	// Formatter is the formatter for the logger. The default is TextFormatter.
 	Formatter Formatter
+	// Background is the terminal background the default styles are built
+	// for. The default is DetectBackground.
+	Background Background
+	// KeyNames are the keys for the built-in fields. The default is
+	// DefaultKeyNames.
+	KeyNames KeyNames
+}
+
+// WithTimeFunction sets the time function for the logger.
+func WithTimeFunction(f TimeFunction) LoggerOption {
+	return func(l *Logger) {
+		if f == nil {
+			f = func(t time.Time) time.Time { return t }
+		}
+		l.timeFunc = f
+	}
+}
+
+// WithTimeFormat sets the time format for the logger.
+func WithTimeFormat(format string) LoggerOption {
+	return func(l *Logger) {
+		if format == "" {
+			format = DefaultTimeFormat
+		}
+		l.timeFormat = format
+	}
+}
+
+// WithTimeEncoder sets the time encoder for the logger. A nil encoder restores
+// the default encoding of the formatter.
+func WithTimeEncoder(enc TimeEncoder) LoggerOption {
+	return func(l *Logger) {
+		l.timeEncoder = enc
+	}
+}
+
+// WithTimestampMode sets the timestamp mode for the TextFormatter.
+func WithTimestampMode(mode TimestampMode) LoggerOption {
+	return func(l *Logger) {
+		l.timestampMode = mode
+	}
+}
+
+// WithAlignment sets how the TextFormatter aligns prefixes and messages.
+func WithAlignment(a Alignment) LoggerOption {
+	return func(l *Logger) {
+		l.alignment = a
+	}
+}
+
+// WithLayout sets the layout mode of the TextFormatter.
+func WithLayout(layout Layout) LoggerOption {
+	return func(l *Logger) {
+		l.layout = layout
+	}
+}
+
+// WithCompact sets whether the TextFormatter elides repeated timestamps and
+// prefixes.
+func WithCompact(compact bool) LoggerOption {
+	return func(l *Logger) {
+		l.compact = compact
+	}
+}
+
+// WithPrettyValues sets how the TextFormatter renders structs, maps, slices,
+// and arrays.
+func WithPrettyValues(p PrettyValues) LoggerOption {
+	return func(l *Logger) {
+		l.pretty = p
+	}
+}
+
+// WithHyperlinks sets the OSC 8 hyperlinks of the TextFormatter.
+func WithHyperlinks(h Hyperlinks) LoggerOption {
+	return func(l *Logger) {
+		l.hyperlinks = h
+	}
+}
+
+// WithBytesEncoding sets the encoding of []byte values in the JSONFormatter
+// and LogfmtFormatter.
+func WithBytesEncoding(e BytesEncoding) LoggerOption {
+	return func(l *Logger) {
+		l.bytesEncoding = e
+	}
+}
+
+// WithHexDumpLimit sets the maximum number of bytes shown in the hex dumps of
+// the TextFormatter.
+func WithHexDumpLimit(limit int) LoggerOption {
+	return func(l *Logger) {
+		l.hexDumpLimit = limit
+	}
+}
+
+// WithLimits sets the limits of the entry sizes.
+func WithLimits(limits Limits) LoggerOption {
+	return func(l *Logger) {
+		l.limits = limits
+	}
+}
+
+// WithSplitMessages sets whether multi-line messages are split into separate
+// entries.
+func WithSplitMessages(split bool) LoggerOption {
+	return func(l *Logger) {
+		l.splitMessages = split
+	}
+}
+
+// WithJSONOptions sets the options of the JSONFormatter.
+func WithJSONOptions(o JSONOptions) LoggerOption {
+	return func(l *Logger) {
+		l.json = o
+	}
+}
+
+// WithCSVOptions sets the columns of the CSVFormatter and TSVFormatter.
+func WithCSVOptions(o CSVOptions) LoggerOption {
+	return func(l *Logger) {
+		l.csv = o
+	}
+}
+
+// WithHTMLOptions sets the options of the HTMLFormatter.
+func WithHTMLOptions(o HTMLOptions) LoggerOption {
+	return func(l *Logger) {
+		l.html = o
+	}
+}
+
+// WithRecorder sets a writer that receives a copy of the output, with its
+// styles in full color, e.g. an AsciicastWriter.
+func WithRecorder(w io.Writer) LoggerOption {
+	return func(l *Logger) {
+		l.recorder = w
+	}
+}
+
+// WithLevel sets the level for the logger.
+func WithLevel(level Level) LoggerOption {
+	return func(l *Logger) {
+		atomic.StoreInt64(&l.level, int64(level))
+	}
+}
+
+// WithLevelEncoder sets the level encoder for the logger. A nil encoder
+// restores the default encoding of the formatter.
+func WithLevelEncoder(enc LevelEncoder) LoggerOption {
+	return func(l *Logger) {
+		l.levelEncoder = enc
+	}
+}
+
+// WithLoggerPrefix sets the prefix for the logger.
+//
+// It's not named WithPrefix because that would clash with the package-level
+// WithPrefix function.
+func WithLoggerPrefix(prefix string) LoggerOption {
+	return func(l *Logger) {
+		l.prefix = prefix
+	}
+}
+
+// WithReportTimestamp sets whether the logger should report the timestamp.
+func WithReportTimestamp(report bool) LoggerOption {
+	return func(l *Logger) {
+		l.reportTimestamp = report
+	}
+}
+
+// WithReportCaller sets whether the logger should report the caller location.
+func WithReportCaller(report bool) LoggerOption {
+	return func(l *Logger) {
+		l.reportCaller = report
+	}
+}
+
+// WithReportStackTrace sets whether the logger should report the stack trace
+// of entries at or above the stack trace level.
+func WithReportStackTrace(report bool) LoggerOption {
+	return func(l *Logger) {
+		l.reportStackTrace = report
+	}
+}
+
+// WithStackTraceLevel sets the minimum level of the entries with a stack
+// trace, when stack traces are reported.
+func WithStackTraceLevel(level Level) LoggerOption {
+	return func(l *Logger) {
+		l.stackTraceLevel = level
+	}
+}
+
+// WithCallerFormatter sets the caller formatter for the logger.
+func WithCallerFormatter(f CallerFormatter) LoggerOption {
+	return func(l *Logger) {
+		if f == nil {
+			f = ShortCallerFormatter
+		}
+		l.callerFormatter = f
+	}
+}
+
+// WithCallerOffset sets the caller offset for the logger.
+func WithCallerOffset(offset int) LoggerOption {
+	return func(l *Logger) {
+		l.callerOffset = offset
+	}
+}
+
+// WithFields appends the given key-value pairs to the logger fields.
+func WithFields(keyvals ...any) LoggerOption {
+	return func(l *Logger) {
+		l.fields = append(l.fields[:len(l.fields):len(l.fields)], keyvals...)
+	}
+}
+
+// WithFormatter sets the formatter for the logger.
+func WithFormatter(f Formatter) LoggerOption {
+	return func(l *Logger) {
+		l.formatter = f
+	}
+}
+
+// WithKeyNames sets the keys used for the built-in fields. Empty names fall
+// back to the package-level defaults.
+func WithKeyNames(k KeyNames) LoggerOption {
+	return func(l *Logger) {
+		l.keys = k.withDefaults()
+	}
+}
+
+// WithStyles sets the styles for the TextFormatter. A nil value resets the
+// styles to DefaultStyles.
+func WithStyles(s *Styles) LoggerOption {
+	return func(l *Logger) {
+		if s == nil {
+			s = DefaultStyles()
+		}
+		l.styles = s
+	}
+}
+
+// WithBackground sets the styles for the TextFormatter to the default styles
+// for the given background.
+func WithBackground(bg Background) LoggerOption {
+	return func(l *Logger) {
+		l.setBackground(bg)
+	}
+}
+
+// WithColorProfile force sets the color profile for the TextFormatter.
+func WithColorProfile(profile colorprofile.Profile) LoggerOption {
+	return func(l *Logger) {
+		l.w.Profile = profile
+	}
+}
+
+// WithHooks appends the given hooks to the logger.
+func WithHooks(hooks ...Hook) LoggerOption {
+	return func(l *Logger) {
+		l.hooks = append(l.hooks[:len(l.hooks):len(l.hooks)], hooks...)
+	}
+}
+
+// WithSinks appends the given loggers as sinks. A sink receives a copy of
+// every entry the logger writes, and filters and formats it according to its
+// own settings. This is useful to write the same entries to different outputs,
+// for example a colorful terminal and a JSON file. Sinks that would form a
+// cycle, like the logger itself, are ignored.
+func WithSinks(sinks ...*Logger) LoggerOption {
+	return func(l *Logger) {
+		l.sinks = l.sinks[:len(l.sinks):len(l.sinks)]
+		for _, sink := range sinks {
+			if sink != nil && !sink.hasSink(l) {
+				l.sinks = append(l.sinks, sink)
+			}
+		}
+	}
 }
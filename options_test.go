@@ -73,3 +73,54 @@ func TestCallerFormatter(t *testing.T) {
 		})
 	}
 }
+
+func TestNewWith(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewWith(&buf,
+		WithLevel(ErrorLevel),
+		WithReportCaller(true),
+		WithFields("foo", "bar"),
+		WithFormatter(JSONFormatter),
+		WithLoggerPrefix("prefix"),
+		WithColorProfile(colorprofile.Ascii),
+	)
+	require.Equal(t, ErrorLevel, logger.GetLevel())
+	require.True(t, logger.reportCaller)
+	require.False(t, logger.reportTimestamp)
+	require.Equal(t, []any{"foo", "bar"}, logger.fields)
+	require.Equal(t, JSONFormatter, logger.formatter)
+	require.Equal(t, "prefix", logger.GetPrefix())
+	require.Equal(t, colorprofile.Ascii, logger.w.Profile)
+	require.Equal(t, DefaultTimeFormat, logger.timeFormat)
+	require.NotNil(t, logger.timeFunc)
+	require.NotNil(t, logger.callerFormatter)
+}
+
+func TestNilOptions(t *testing.T) {
+	logger := NewWith(io.Discard,
+		WithTimeFunction(nil),
+		WithTimeFormat(""),
+		WithCallerFormatter(nil),
+		WithStyles(nil),
+	)
+	require.NotNil(t, logger.timeFunc)
+	require.NotNil(t, logger.callerFormatter)
+	require.Equal(t, DefaultTimeFormat, logger.timeFormat)
+	require.Equal(t, DefaultStyles(), logger.styles)
+}
+
+func TestWithOptions(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWith(&buf, WithFields("a", 1))
+	sl := l.WithOptions(WithFields("b", 2), WithLevel(DebugLevel))
+
+	sl.Debug("debug")
+	l.Debug("debug")
+	require.Equal(t, "DEBU debug a=1 b=2\n", buf.String())
+
+	buf.Reset()
+	l.SetOptions(WithLoggerPrefix("p"))
+	l.Info("info")
+	sl.Info("info")
+	require.Equal(t, "INFO p: info a=1\nINFO info a=1 b=2\n", buf.String())
+}
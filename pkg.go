@@ -45,27 +45,57 @@ func New(w io.Writer) *Logger {
 
 // NewWithOptions returns a new logger using the provided options.
 func NewWithOptions(w io.Writer, o Options) *Logger {
+	return newLogger(w, o)
+}
+
+// NewWith returns a new logger with the given options applied.
+func NewWith(w io.Writer, opts ...LoggerOption) *Logger {
+	return newLogger(w, Options{}, opts...)
+}
+
+// newLogger returns a new logger using the provided options, then applies
+// opts, before setting the default styles if opts didn't set any.
+func newLogger(w io.Writer, o Options, opts ...LoggerOption) *Logger {
 	l := &Logger{
-		b:               bytes.Buffer{},
-		mu:              &sync.RWMutex{},
-		helpers:         &sync.Map{},
-		level:           int64(o.Level),
-		reportTimestamp: o.ReportTimestamp,
-		reportCaller:    o.ReportCaller,
-		prefix:          o.Prefix,
-		timeFunc:        o.TimeFunction,
-		timeFormat:      o.TimeFormat,
-		formatter:       o.Formatter,
-		fields:          o.Fields,
-		callerFormatter: o.CallerFormatter,
-		callerOffset:    o.CallerOffset,
+		b:                bytes.Buffer{},
+		mu:               &sync.RWMutex{},
+		helpers:          &sync.Map{},
+		state:            newTextState(),
+		level:            int64(o.Level),
+		reportTimestamp:  o.ReportTimestamp,
+		reportCaller:     o.ReportCaller,
+		reportStackTrace: o.ReportStackTrace,
+		stackTraceLevel:  ErrorLevel,
+		prefix:           o.Prefix,
+		timeFunc:         o.TimeFunction,
+		timeFormat:       o.TimeFormat,
+		timeEncoder:      o.TimeEncoder,
+		timestampMode:    o.TimestampMode,
+		alignment:        o.Alignment,
+		layout:           o.Layout,
+		compact:          o.Compact,
+		pretty:           o.PrettyValues,
+		hyperlinks:       o.Hyperlinks,
+		bytesEncoding:    o.BytesEncoding,
+		hexDumpLimit:     o.HexDumpLimit,
+		limits:           o.Limits,
+		splitMessages:    o.SplitMessages,
+		json:             o.JSON,
+		csv:              o.CSV,
+		html:             o.HTML,
+		recorder:         o.Recorder,
+		levelEncoder:     o.LevelEncoder,
+		formatter:        o.Formatter,
+		fields:           o.Fields,
+		callerFormatter:  o.CallerFormatter,
+		callerOffset:     o.CallerOffset,
+		keys:             o.KeyNames.withDefaults(),
 	}
 
 	l.SetOutput(w)
 	// Detect color profile from the writer and environment.
 	l.SetColorProfile(colorprofile.Detect(w, os.Environ()))
 	l.SetLevel(Level(l.level))
-	l.SetStyles(DefaultStyles())
 
 	if l.callerFormatter == nil {
 		l.callerFormatter = ShortCallerFormatter
@@ -79,6 +109,15 @@ func NewWithOptions(w io.Writer, o Options) *Logger {
 		l.timeFormat = DefaultTimeFormat
 	}
 
+	if o.StackTraceLevel != nil {
+		l.stackTraceLevel = *o.StackTraceLevel
+	}
+
+	l.SetOptions(opts...)
+	if l.styles == nil {
+		l.setBackground(o.Background)
+	}
+
 	return l
 }
 
@@ -148,6 +187,11 @@ func SetStyles(s *Styles) {
 	Default().SetStyles(s)
 }
 
+// SetOptions applies the given options to the default logger.
+func SetOptions(opts ...LoggerOption) {
+	Default().SetOptions(opts...)
+}
+
 // GetPrefix returns the prefix for the default logger.
 func GetPrefix() string {
 	return Default().GetPrefix()
@@ -242,6 +286,38 @@ func Printf(format string, args ...any) {
 	Default().Log(noLevel, fmt.Sprintf(format, args...))
 }
 
+// Logt logs a message template with the given keyvals for the given level.
+// See Logger.Logt.
+func Logt(level Level, template string, keyvals ...any) {
+	Default().Log(level, Default().template(template, keyvals), keyvals...)
+}
+
+// Debugt logs a debug message template.
+func Debugt(template string, keyvals ...any) {
+	Default().Log(DebugLevel, Default().template(template, keyvals), keyvals...)
+}
+
+// Infot logs an info message template.
+func Infot(template string, keyvals ...any) {
+	Default().Log(InfoLevel, Default().template(template, keyvals), keyvals...)
+}
+
+// Warnt logs a warning message template.
+func Warnt(template string, keyvals ...any) {
+	Default().Log(WarnLevel, Default().template(template, keyvals), keyvals...)
+}
+
+// Errort logs an error message template.
+func Errort(template string, keyvals ...any) {
+	Default().Log(ErrorLevel, Default().template(template, keyvals), keyvals...)
+}
+
+// Fatalt logs a fatal message template and exit.
+func Fatalt(template string, keyvals ...any) {
+	Default().Log(FatalLevel, Default().template(template, keyvals), keyvals...)
+	os.Exit(1)
+}
+
 // StandardLog returns a standard logger from the default logger.
 func StandardLog(opts ...StandardLogOptions) *log.Logger {
 	return Default().StandardLog(opts...)
@@ -240,5 +240,5 @@ func TestGlobalCustomLevel(t *testing.T) {
 	SetFormatter(JSONFormatter)
 	Log(lvl, "info")
 	Logf(lvl, "hey %s", "you")
-	assert.Equal(t, "{\"msg\":\"info\"}\n{\"msg\":\"hey you\"}\n", buf.String())
+	assert.Equal(t, "{\"level\":\"debug+3\",\"msg\":\"info\"}\n{\"level\":\"debug+3\",\"msg\":\"hey you\"}\n", buf.String())
 }
@@ -0,0 +1,270 @@
+package log
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+const (
+	defaultPrettyMaxDepth = 5
+	defaultPrettyMaxItems = 20
+)
+
+// PrettyValues controls how the TextFormatter renders structs, maps, slices,
+// and arrays. When enabled, they're rendered as an indented tree below their
+// key, instead of on a single line.
+type PrettyValues struct {
+	// Enabled turns on pretty rendering.
+	Enabled bool
+	// MaxDepth is the maximum nesting depth. Deeper values are elided. The
+	// default is 5.
+	MaxDepth int
+	// MaxItems is the maximum number of entries rendered per struct, map,
+	// slice, or array. The remaining entries are elided. The default is 20.
+	MaxItems int
+}
+
+// prettyKey identifies a reference on the path being rendered, to detect
+// cycles.
+type prettyKey struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+type prettyPrinter struct {
+	st       *Styles
+	key      string
+	maxDepth int
+	maxItems int
+	visiting map[prettyKey]bool
+	lines    []string
+}
+
+// prettyLines renders v as an indented tree, with one string per line. It
+// returns nil if v isn't a struct, map, slice, or array, if v knows how to
+// print itself, or if it fits on a single line.
+func prettyLines(v any, st *Styles, key string, opts PrettyValues) []string {
+	if _, ok := prettyValue(reflect.ValueOf(v)); !ok {
+		return nil
+	}
+
+	p := prettyPrinter{
+		st:       st,
+		key:      key,
+		maxDepth: opts.MaxDepth,
+		maxItems: opts.MaxItems,
+		visiting: map[prettyKey]bool{},
+	}
+	if p.maxDepth <= 0 {
+		p.maxDepth = defaultPrettyMaxDepth
+	}
+	if p.maxItems <= 0 {
+		p.maxItems = defaultPrettyMaxItems
+	}
+	p.render("", "", reflect.ValueOf(v), 0)
+	if len(p.lines) < 2 {
+		return nil
+	}
+	return p.lines
+}
+
+// prettyValue dereferences pointers and interfaces, and reports whether the
+// resulting value should be rendered as a tree.
+func prettyValue(v reflect.Value) (reflect.Value, bool) {
+	for v.IsValid() {
+		if printsItself(v) {
+			return v, false
+		}
+		switch v.Kind() { //nolint:exhaustive
+		case reflect.Pointer, reflect.Interface:
+			if v.IsNil() {
+				return v, false
+			}
+			v = v.Elem()
+		case reflect.Struct, reflect.Map, reflect.Array:
+			return v, true
+		case reflect.Slice:
+			// Byte slices are rendered as strings.
+			return v, v.Type().Elem().Kind() != reflect.Uint8
+		default:
+			return v, false
+		}
+	}
+	return v, false
+}
+
+// printsItself reports whether v implements error or fmt.Stringer, in which
+// case it's rendered with its own representation.
+func printsItself(v reflect.Value) bool {
+	if !v.CanInterface() {
+		return false
+	}
+	if v.Kind() == reflect.Pointer && v.IsNil() {
+		return false
+	}
+	switch v.Interface().(type) {
+	case error, fmt.Stringer:
+		return true
+	}
+	return false
+}
+
+func (p *prettyPrinter) line(s string) {
+	p.lines = append(p.lines, s)
+}
+
+// render writes the lines of v, prefixed with indent and label.
+func (p *prettyPrinter) render(indent, label string, v reflect.Value, depth int) {
+	// Follow pointers, tracking them to detect cycles.
+	for v.IsValid() && !printsItself(v) &&
+		(v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			p.line(indent + label + p.st.valueStyle(p.key, nil).Render("<nil>"))
+			return
+		}
+		if v.Kind() == reflect.Pointer {
+			k := prettyKey{v.Pointer(), v.Type()}
+			if p.visiting[k] {
+				p.line(indent + label + p.st.Bracket.Render("<cycle>"))
+				return
+			}
+			p.visiting[k] = true
+			defer delete(p.visiting, k)
+		}
+		v = v.Elem()
+	}
+
+	rv, ok := prettyValue(v)
+	if !ok {
+		p.line(indent + label + p.leaf(v))
+		return
+	}
+	v = rv
+
+	open, closing := "[", "]"
+	if v.Kind() == reflect.Struct || v.Kind() == reflect.Map {
+		open, closing = "{", "}"
+	}
+
+	n := v.Len
+	if v.Kind() == reflect.Struct {
+		n = v.NumField
+	}
+	switch {
+	case n() == 0:
+		p.line(indent + label + p.st.Bracket.Render(open+closing))
+		return
+	case depth >= p.maxDepth:
+		p.line(indent + label + p.st.Bracket.Render(open+"…"+closing))
+		return
+	}
+
+	if v.Kind() == reflect.Map || v.Kind() == reflect.Slice {
+		k := prettyKey{v.Pointer(), v.Type()}
+		if p.visiting[k] {
+			p.line(indent + label + p.st.Bracket.Render("<cycle>"))
+			return
+		}
+		p.visiting[k] = true
+		defer delete(p.visiting, k)
+	}
+
+	p.line(indent + label + p.st.Bracket.Render(open))
+	inner := indent + "  "
+	items := min(n(), p.maxItems)
+	switch v.Kind() { //nolint:exhaustive
+	case reflect.Struct:
+		t := v.Type()
+		for i := range items {
+			p.render(inner, p.label(t.Field(i).Name), v.Field(i), depth+1)
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		names := make([]string, len(keys))
+		for i, k := range keys {
+			names[i] = fmt.Sprint(k)
+		}
+		sort.Sort(mapKeys{keys, names})
+		for i := range items {
+			p.render(inner, p.label(names[i]), v.MapIndex(keys[i]), depth+1)
+		}
+	default:
+		for i := range items {
+			p.render(inner, "", v.Index(i), depth+1)
+		}
+	}
+	if more := n() - items; more > 0 {
+		p.line(inner + p.st.Bracket.Render(fmt.Sprintf("… (%d more)", more)))
+	}
+	p.line(indent + p.st.Bracket.Render(closing))
+}
+
+// label returns the styled key of a struct field or map entry.
+func (p *prettyPrinter) label(key string) string {
+	if key == "" || needsQuoting(key) {
+		key = strconv.Quote(key)
+	}
+	return p.st.NestedKey.Render(key) + p.st.Separator.Render(":") + " "
+}
+
+// leaf returns the styled representation of a scalar value.
+func (p *prettyPrinter) leaf(v reflect.Value) string {
+	if !v.IsValid() {
+		return p.st.valueStyle(p.key, nil).Render("<nil>")
+	}
+
+	var (
+		s  string
+		iv any
+	)
+	switch {
+	case v.CanInterface():
+		iv = v.Interface()
+		s = fmt.Sprintf("%+v", iv)
+	case v.Kind() == reflect.String:
+		iv = v.String()
+		s = v.String()
+	default:
+		// Unexported fields can't be converted to interfaces, but fmt
+		// knows how to print the value they hold.
+		s = fmt.Sprintf("%+v", v)
+	}
+	if v.Kind() == reflect.String && (s == "" || needsQuoting(s)) {
+		s = strconv.Quote(s)
+	}
+	st := p.st.Value
+	if iv != nil {
+		st = p.st.valueStyle(p.key, iv)
+	} else if vs, ok := p.st.Values[p.key]; ok {
+		st = vs
+	}
+	return st.Render(escapeStringForOutput(s, false))
+}
+
+// mapKeys sorts map keys by their string representation.
+type mapKeys struct {
+	keys  []reflect.Value
+	names []string
+}
+
+func (m mapKeys) Len() int           { return len(m.keys) }
+func (m mapKeys) Less(i, j int) bool { return m.names[i] < m.names[j] }
+func (m mapKeys) Swap(i, j int) {
+	m.keys[i], m.keys[j] = m.keys[j], m.keys[i]
+	m.names[i], m.names[j] = m.names[j], m.names[i]
+}
+
+// writeLines writes lines prefixed with indent, like writeIndent, without
+// escaping or styling them.
+func writeLines(w io.Writer, lines []string, indent string, newline bool) {
+	for i, line := range lines {
+		_, _ = io.WriteString(w, indent)
+		_, _ = io.WriteString(w, line)
+		if newline || i < len(lines)-1 {
+			_, _ = w.Write([]byte{'\n'})
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrettyValues(t *testing.T) {
+	type address struct {
+		City string
+		Zip  string
+	}
+	type user struct {
+		ID      int
+		Tags    []string
+		Address *address
+		Meta    map[string]any
+		Err     error
+		secret  string
+	}
+
+	var buf bytes.Buffer
+	l := New(&buf)
+	l.SetOptions(WithPrettyValues(PrettyValues{Enabled: true}))
+
+	cases := []struct {
+		name     string
+		keyvals  []any
+		expected string
+	}{
+		{
+			name: "struct",
+			keyvals: []any{"user", user{
+				ID:      3,
+				Tags:    []string{"a", "b c"},
+				Address: &address{City: "Paris"},
+				Meta:    map[string]any{"z": 1, "a": []int{}},
+				Err:     errors.New("boom"),
+				secret:  "s",
+			}, "ok", true},
+			expected: "INFO msg\n" +
+				"  user=\n" +
+				"  │ {\n" +
+				"  │   ID: 3\n" +
+				"  │   Tags: [\n" +
+				"  │     a\n" +
+				"  │     \"b c\"\n" +
+				"  │   ]\n" +
+				"  │   Address: {\n" +
+				"  │     City: Paris\n" +
+				"  │     Zip: \"\"\n" +
+				"  │   }\n" +
+				"  │   Meta: {\n" +
+				"  │     a: []\n" +
+				"  │     z: 1\n" +
+				"  │   }\n" +
+				"  │   Err: boom\n" +
+				"  │   secret: s\n" +
+				"  │ }\n" +
+				" ok=true\n",
+		},
+		{
+			name:     "empty",
+			keyvals:  []any{"tags", []string{}, "m", map[int]int(nil)},
+			expected: "INFO msg tags=[] m=map[]\n",
+		},
+		{
+			name:     "stringer",
+			keyvals:  []any{"lvl", InfoLevel, "err", errors.New("boom")},
+			expected: "INFO msg lvl=info err=boom\n",
+		},
+		{
+			name:    "map keys",
+			keyvals: []any{"m", map[int]string{2: "b", 10: "j", 1: "a"}},
+			expected: "INFO msg\n" +
+				"  m=\n" +
+				"  │ {\n" +
+				"  │   1: a\n" +
+				"  │   10: j\n" +
+				"  │   2: b\n" +
+				"  │ }\n",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			buf.Reset()
+			l.Info("msg", c.keyvals...)
+			assert.Equal(t, c.expected, buf.String())
+		})
+	}
+}
+
+func TestPrettyValuesLimits(t *testing.T) {
+	type node struct {
+		Name string
+		Next *node
+	}
+
+	var buf bytes.Buffer
+	l := New(&buf)
+	l.SetOptions(WithPrettyValues(PrettyValues{Enabled: true, MaxDepth: 2, MaxItems: 2}))
+
+	t.Run("items", func(t *testing.T) {
+		buf.Reset()
+		l.Info("msg", "s", []int{1, 2, 3, 4})
+		assert.Equal(t, "INFO msg\n"+
+			"  s=\n"+
+			"  │ [\n"+
+			"  │   1\n"+
+			"  │   2\n"+
+			"  │   … (2 more)\n"+
+			"  │ ]\n", buf.String())
+	})
+
+	t.Run("depth", func(t *testing.T) {
+		buf.Reset()
+		l.Info("msg", "s", [][][]int{{{1}}})
+		assert.Equal(t, "INFO msg\n"+
+			"  s=\n"+
+			"  │ [\n"+
+			"  │   [\n"+
+			"  │     […]\n"+
+			"  │   ]\n"+
+			"  │ ]\n", buf.String())
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		buf.Reset()
+		l.SetOptions(WithPrettyValues(PrettyValues{Enabled: true}))
+		n := &node{Name: "a"}
+		n.Next = n
+		l.Info("msg", "n", n)
+		assert.Equal(t, "INFO msg\n"+
+			"  n=\n"+
+			"  │ {\n"+
+			"  │   Name: a\n"+
+			"  │   Next: <cycle>\n"+
+			"  │ }\n", buf.String())
+	})
+}
@@ -0,0 +1,14 @@
+//go:build !unix
+
+package log
+
+import "sync/atomic"
+
+var resizeGen atomic.Uint64
+
+// resizeGeneration returns a number that changes whenever the terminal may
+// have been resized. Without SIGWINCH, it changes on every call, so the
+// terminal size is queried for every entry.
+func resizeGeneration() uint64 {
+	return resizeGen.Add(1)
+}
@@ -0,0 +1,31 @@
+//go:build unix
+
+package log
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+)
+
+var (
+	resizeOnce sync.Once
+	resizeGen  atomic.Uint64
+)
+
+// resizeGeneration returns a number that changes whenever the terminal is
+// resized. It starts watching for SIGWINCH on the first call.
+func resizeGeneration() uint64 {
+	resizeOnce.Do(func() {
+		ch := make(chan os.Signal, 1)
+		signal.Notify(ch, syscall.SIGWINCH)
+		go func() {
+			for range ch {
+				resizeGen.Add(1)
+			}
+		}()
+	})
+	return resizeGen.Load()
+}
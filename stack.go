@@ -0,0 +1,100 @@
+package log
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// stackTrace is the stack of an entry, starting at the caller.
+type stackTrace []runtime.Frame
+
+// stackFrame is a frame of a stack trace in structured formats.
+type stackFrame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// String returns the stack like the Go runtime prints it in panics, with a
+// line for the function, and an indented line for the file and line number
+// of each frame.
+func (s stackTrace) String() string {
+	var b strings.Builder
+	for i, f := range s {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(f.Function)
+		b.WriteString("\n  ")
+		b.WriteString(f.File)
+		b.WriteByte(':')
+		b.WriteString(strconv.Itoa(f.Line))
+	}
+	return b.String()
+}
+
+// frames returns the frames of the stack for structured formats.
+func (s stackTrace) frames() []stackFrame {
+	frames := make([]stackFrame, len(s))
+	for i, f := range s {
+		frames[i] = stackFrame{Function: f.Function, File: f.File, Line: f.Line}
+	}
+	return frames
+}
+
+// callerFrames returns the frame of the caller, skipping helper functions.
+// With stack set, the frames of the rest of the stack follow, except the
+// goroutine entry point.
+func (l *Logger) callerFrames(skip int, stack bool) []runtime.Frame {
+	// Skip l.callerFrames.
+	frames := l.frames(skip + 1)
+	for {
+		f, more := frames.Next()
+		_, helper := l.helpers.Load(f.Function)
+		if helper && more {
+			continue
+		}
+
+		// Found a frame that wasn't a helper function.
+		// Or we ran out of frames to check.
+		caller := []runtime.Frame{f}
+		for stack && more {
+			f, more = frames.Next()
+			if f.Function != "runtime.goexit" {
+				caller = append(caller, f)
+			}
+		}
+		return caller
+	}
+}
+
+// reportsStack reports whether the logger reports the stack of entries at
+// the given level.
+func (l *Logger) reportsStack(level Level) bool {
+	return l.stack || l.reportStackTrace && level != noLevel && level >= l.stackTraceLevel
+}
+
+// wantsStack reports whether the logger, or any of its sinks, reports the
+// stack of entries at the given level.
+func (l *Logger) wantsStack(level Level) bool {
+	if l.reportsStack(level) {
+		return true
+	}
+	for _, sink := range l.sinks {
+		if sink.wantsStack(level) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithStack returns a new logger that reports the stack trace of its
+// entries, e.g.
+//
+//	logger.WithStack().Error("failed to connect", "err", err)
+func (l *Logger) WithStack() *Logger {
+	sl := l.With()
+	sl.stack = true
+	return sl
+}
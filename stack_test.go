@@ -0,0 +1,133 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStackTraceString(t *testing.T) {
+	s := stackTrace{
+		{Function: "main.run", File: "/src/main.go", Line: 12},
+		{Function: "main.main", File: "/src/main.go", Line: 5},
+	}
+	assert.Equal(t, "main.run\n  /src/main.go:12\nmain.main\n  /src/main.go:5", s.String())
+}
+
+func TestWithStack(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf)
+	l.Info("no stack")
+	_, _, line, _ := runtime.Caller(0)
+	l.WithStack().Error("boom", "a", 1)
+	l.Info("no stack")
+
+	lines := strings.Split(buf.String(), "\n")
+	assert.Equal(t, []string{
+		"INFO no stack",
+		"ERRO boom a=1",
+		"  stack=",
+		"  │ charm.land/log/v2.TestWithStack",
+	}, lines[:4])
+	assert.True(t, strings.HasSuffix(lines[4], fmt.Sprintf("log/stack_test.go:%d", line+1)), lines[4])
+	assert.Contains(t, buf.String(), "\nINFO no stack\n")
+}
+
+func TestStackTraceLevel(t *testing.T) {
+	var buf bytes.Buffer
+	level := ErrorLevel
+	l := NewWithOptions(&buf, Options{
+		Formatter:        LogfmtFormatter,
+		ReportStackTrace: true,
+		StackTraceLevel:  &level,
+	})
+	l.Warn("warn")
+	l.Print("print")
+	l.Error("error")
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, "level=warn msg=warn", lines[0])
+	assert.Equal(t, "msg=print", lines[1])
+	assert.True(t, strings.HasPrefix(lines[2],
+		`level=error msg=error stack="charm.land/log/v2.TestStackTraceLevel\n  `), lines[2])
+
+	buf.Reset()
+	l.SetOptions(WithReportStackTrace(false))
+	l.Error("error")
+	assert.Equal(t, "level=error msg=error\n", buf.String())
+}
+
+func TestStackTraceJSON(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf)
+	l.SetFormatter(JSONFormatter)
+	l.SetOptions(WithReportStackTrace(true))
+	l.SetOptions(WithKeyNames(KeyNames{Stack: "trace"}))
+
+	helper := func() {
+		l.Helper()
+		l.Error("from helper")
+	}
+	_, file, line, _ := runtime.Caller(0)
+	helper()
+
+	var entry struct {
+		Trace []stackFrame `json:"trace"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	require.NotEmpty(t, entry.Trace)
+	assert.Equal(t, stackFrame{
+		Function: "charm.land/log/v2.TestStackTraceJSON",
+		File:     file,
+		Line:     line + 1,
+	}, entry.Trace[0])
+	for _, f := range entry.Trace {
+		assert.NotEqual(t, "runtime.goexit", f.Function)
+	}
+}
+
+func TestStackTraceSink(t *testing.T) {
+	var out, sinkOut bytes.Buffer
+	sink := New(&sinkOut)
+	sink.SetOptions(WithReportStackTrace(true))
+	l := New(&out)
+	l.SetOptions(WithSinks(sink))
+	l.Info("msg")
+	l.Error("msg")
+	assert.Equal(t, "INFO msg\nERRO msg\n", out.String())
+	assert.True(t, strings.HasPrefix(sinkOut.String(), "INFO msg\nERRO msg\n  stack=\n"), sinkOut.String())
+	assert.Contains(t, sinkOut.String(), "│ charm.land/log/v2.TestStackTraceSink\n")
+}
+
+func TestStackTraceLevelDefault(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWithOptions(&buf, Options{Formatter: LogfmtFormatter, ReportStackTrace: true})
+	l.Warn("warn")
+	assert.Equal(t, "level=warn msg=warn\n", buf.String())
+
+	buf.Reset()
+	l.SetOptions(WithStackTraceLevel(InfoLevel))
+	l.Info("info")
+	assert.Contains(t, buf.String(), "level=info msg=info stack=")
+}
+
+func TestStackTraceLevelInfo(t *testing.T) {
+	var buf bytes.Buffer
+	level := InfoLevel
+	l := NewWithOptions(&buf, Options{
+		Formatter:        LogfmtFormatter,
+		ReportStackTrace: true,
+		StackTraceLevel:  &level,
+	})
+	l.Debug("debug")
+	l.Info("info")
+	assert.Contains(t, buf.String(), "level=info msg=info stack=")
+	assert.NotContains(t, buf.String(), "debug")
+}
@@ -1,9 +1,14 @@
 package log
 
 import (
+	"io"
+	"os"
 	"strings"
+	"sync"
 
 	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/colorprofile"
+	"github.com/charmbracelet/x/term"
 )
 
 // Styles defines the styles for the text logger.
@@ -11,6 +16,12 @@ type Styles struct {
 	// Timestamp is the style for timestamps.
 	Timestamp lipgloss.Style
 
+	// Elapsed is the style for timestamps in the ElapsedTimestamp mode.
+	Elapsed lipgloss.Style
+
+	// Delta is the style for timestamps in the DeltaTimestamp mode.
+	Delta lipgloss.Style
+
 	// Caller is the style for source caller.
 	Caller lipgloss.Style
 
@@ -29,6 +40,14 @@ type Styles struct {
 	// Separator is the style for separators.
 	Separator lipgloss.Style
 
+	// NestedKey is the style for struct fields and map keys of values
+	// rendered with PrettyValues.
+	NestedKey lipgloss.Style
+
+	// Bracket is the style for brackets and elisions of values rendered with
+	// PrettyValues.
+	Bracket lipgloss.Style
+
 	// Levels are the styles for each level.
 	Levels map[Level]lipgloss.Style
 
@@ -37,47 +56,246 @@ type Styles struct {
 
 	// Values overrides value styles for specific keys.
 	Values map[string]lipgloss.Style
+
+	// Kinds overrides value styles for specific value kinds. Styles for
+	// specific keys in Values take precedence.
+	Kinds map[ValueKind]lipgloss.Style
+
+	// LevelOverrides overrides styles for entries of specific levels.
+	LevelOverrides map[Level]LevelStyles
+
+	// JSON is the styles of the JSONFormatter syntax highlighting.
+	JSON JSONStyles
+}
+
+// LevelStyles overrides the styles of entries of a specific level. Nil styles
+// keep the styles of the Styles they belong to.
+type LevelStyles struct {
+	// Message overrides the style for messages.
+	Message *lipgloss.Style
+
+	// Key overrides the style for keys.
+	Key *lipgloss.Style
+
+	// Value overrides the style for values.
+	Value *lipgloss.Style
+
+	// Separator overrides the style for separators.
+	Separator *lipgloss.Style
+
+	// Line is inherited by the styles of every part of the line, e.g. to tint
+	// the whole line with a color. Properties set on the other styles take
+	// precedence.
+	Line *lipgloss.Style
+}
+
+// stylesForLevel returns the effective styles for entries of the given level.
+// They're computed once for each Styles set on the logger.
+func (l *Logger) stylesForLevel(level Level) *Styles {
+	if l.levelStylesOf != l.styles {
+		// The styles of levels without overrides are stored under noLevel.
+		l.levelStyles = make(map[Level]*Styles, len(l.styles.LevelOverrides)+1)
+		l.levelStyles[noLevel] = l.styles.forLevel(noLevel)
+		for lvl := range l.styles.LevelOverrides {
+			l.levelStyles[lvl] = l.styles.forLevel(lvl)
+		}
+		l.levelStylesOf = l.styles
+	}
+	if st, ok := l.levelStyles[level]; ok {
+		return st
+	}
+	return l.levelStyles[noLevel]
+}
+
+// forLevel returns the effective styles for entries of the given level.
+func (s *Styles) forLevel(level Level) *Styles {
+	o := s.LevelOverrides[level]
+	r := *s
+	for _, f := range []struct {
+		override *lipgloss.Style
+		style    *lipgloss.Style
+	}{
+		{o.Message, &r.Message},
+		{o.Key, &r.Key},
+		{o.Value, &r.Value},
+		{o.Separator, &r.Separator},
+	} {
+		if f.override != nil {
+			*f.style = *f.override
+		}
+	}
+
+	if o.Line != nil {
+		line := *o.Line
+		for _, st := range []*lipgloss.Style{
+			&r.Timestamp, &r.Elapsed, &r.Delta, &r.Caller, &r.Prefix,
+			&r.Message, &r.Key, &r.Value, &r.Separator, &r.NestedKey,
+			&r.Bracket,
+		} {
+			*st = st.Inherit(line)
+		}
+		r.Levels = inheritAll(s.Levels, line)
+		r.Keys = inheritAll(s.Keys, line)
+		r.Values = inheritAll(s.Values, line)
+		r.Kinds = inheritAll(s.Kinds, line)
+	}
+
+	return &r
+}
+
+func inheritAll[K comparable](m map[K]lipgloss.Style, parent lipgloss.Style) map[K]lipgloss.Style {
+	r := make(map[K]lipgloss.Style, len(m))
+	for k, st := range m {
+		r[k] = st.Inherit(parent)
+	}
+	return r
 }
 
-// DefaultStyles returns the default styles.
+// Background is the terminal background color the default styles are built
+// for.
+type Background uint8
+
+const (
+	// DetectBackground queries the terminal for its background color, and
+	// falls back to DarkBackground if the output isn't a terminal or the
+	// background can't be detected. The terminal is queried when the first
+	// styled entry is written, at most once per process. This is the
+	// default.
+	DetectBackground Background = iota
+	// DarkBackground builds the default styles for a dark background.
+	DarkBackground
+	// LightBackground builds the default styles for a light background.
+	LightBackground
+)
+
+// DefaultStyles returns the default styles for a dark background.
 func DefaultStyles() *Styles {
-	// TODO handle this based on light/dark colors
+	return AdaptiveStyles(true)
+}
+
+// AdaptiveStyles returns the default styles with colors adapted to a dark or
+// light background. Colors are downsampled to the color profile of the
+// output when written.
+func AdaptiveStyles(hasDarkBG bool) *Styles {
+	lightDark := lipgloss.LightDark(hasDarkBG)
 	return &Styles{
 		Timestamp: lipgloss.NewStyle(),
+		Elapsed:   lipgloss.NewStyle(),
+		Delta:     lipgloss.NewStyle().Faint(true),
 		Caller:    lipgloss.NewStyle().Faint(true),
 		Prefix:    lipgloss.NewStyle().Bold(true).Faint(true),
 		Message:   lipgloss.NewStyle(),
 		Key:       lipgloss.NewStyle().Faint(true),
 		Value:     lipgloss.NewStyle(),
 		Separator: lipgloss.NewStyle().Faint(true),
+		NestedKey: lipgloss.NewStyle().Faint(true),
+		Bracket:   lipgloss.NewStyle().Faint(true),
 		Levels: map[Level]lipgloss.Style{
 			DebugLevel: lipgloss.NewStyle().
 				SetString(strings.ToUpper(DebugLevel.String())).
 				Bold(true).
 				MaxWidth(4).
-				Foreground(lipgloss.Color("63")),
+				Foreground(lightDark(lipgloss.Color("62"), lipgloss.Color("63"))),
 			InfoLevel: lipgloss.NewStyle().
 				SetString(strings.ToUpper(InfoLevel.String())).
 				Bold(true).
 				MaxWidth(4).
-				Foreground(lipgloss.Color("86")),
+				Foreground(lightDark(lipgloss.Color("30"), lipgloss.Color("86"))),
 			WarnLevel: lipgloss.NewStyle().
 				SetString(strings.ToUpper(WarnLevel.String())).
 				Bold(true).
 				MaxWidth(4).
-				Foreground(lipgloss.Color("192")),
+				Foreground(lightDark(lipgloss.Color("136"), lipgloss.Color("192"))),
 			ErrorLevel: lipgloss.NewStyle().
 				SetString(strings.ToUpper(ErrorLevel.String())).
 				Bold(true).
 				MaxWidth(4).
-				Foreground(lipgloss.Color("204")),
+				Foreground(lightDark(lipgloss.Color("161"), lipgloss.Color("204"))),
 			FatalLevel: lipgloss.NewStyle().
 				SetString(strings.ToUpper(FatalLevel.String())).
 				Bold(true).
 				MaxWidth(4).
-				Foreground(lipgloss.Color("134")),
+				Foreground(lightDark(lipgloss.Color("91"), lipgloss.Color("134"))),
 		},
 		Keys:   map[string]lipgloss.Style{},
 		Values: map[string]lipgloss.Style{},
+		Kinds: map[ValueKind]lipgloss.Style{
+			NumberValue:   lipgloss.NewStyle().Foreground(lightDark(lipgloss.Color("31"), lipgloss.Color("117"))),
+			BoolValue:     lipgloss.NewStyle().Foreground(lightDark(lipgloss.Color("163"), lipgloss.Color("212"))),
+			NilValue:      lipgloss.NewStyle().Faint(true),
+			DurationValue: lipgloss.NewStyle().Foreground(lightDark(lipgloss.Color("130"), lipgloss.Color("180"))),
+			TimeValue:     lipgloss.NewStyle().Foreground(lightDark(lipgloss.Color("29"), lipgloss.Color("151"))),
+			ErrorValue:    lipgloss.NewStyle().Foreground(lightDark(lipgloss.Color("161"), lipgloss.Color("204"))),
+			URLValue:      lipgloss.NewStyle().Underline(true).Foreground(lightDark(lipgloss.Color("27"), lipgloss.Color("39"))),
+			PathValue:     lipgloss.NewStyle().Foreground(lightDark(lipgloss.Color("136"), lipgloss.Color("222"))),
+			StringValue:   lipgloss.NewStyle().Foreground(lightDark(lipgloss.Color("64"), lipgloss.Color("150"))),
+		},
+		LevelOverrides: map[Level]LevelStyles{},
+		JSON: JSONStyles{
+			Key:         lipgloss.NewStyle().Foreground(lightDark(lipgloss.Color("25"), lipgloss.Color("75"))),
+			String:      lipgloss.NewStyle().Foreground(lightDark(lipgloss.Color("64"), lipgloss.Color("150"))),
+			Number:      lipgloss.NewStyle().Foreground(lightDark(lipgloss.Color("31"), lipgloss.Color("117"))),
+			Bool:        lipgloss.NewStyle().Foreground(lightDark(lipgloss.Color("163"), lipgloss.Color("212"))),
+			Null:        lipgloss.NewStyle().Faint(true),
+			Punctuation: lipgloss.NewStyle().Faint(true),
+		},
+	}
+}
+
+// file is a terminal file, see github.com/charmbracelet/x/term.File.
+type file interface {
+	io.ReadWriteCloser
+	Fd() uintptr
+}
+
+// background is the terminal background detected by hasDarkBackground.
+var background struct {
+	once sync.Once
+	dark bool
+}
+
+// hasDarkBackground reports whether w is a terminal with a dark background.
+// It returns true if w isn't a terminal or the background can't be detected.
+// The terminal is queried at most once per process, since the query reads
+// from stdin and can take a while on terminals that don't answer it.
+func hasDarkBackground(w io.Writer) bool {
+	if cw, ok := w.(*colorprofile.Writer); ok {
+		w = cw.Forward
+	}
+	f, ok := w.(file)
+	if !ok || !term.IsTerminal(f.Fd()) {
+		return true
+	}
+	background.once.Do(func() {
+		background.dark = lipgloss.HasDarkBackground(os.Stdin, f)
+	})
+	return background.dark
+}
+
+// setBackground sets the styles to the default styles for the given
+// background. Detecting the background is deferred to the first entry the
+// TextFormatter or HTMLFormatter writes, until then the styles are the ones
+// for a dark background.
+func (l *Logger) setBackground(bg Background) {
+	switch bg {
+	case DarkBackground:
+		l.styles, l.detectStyles = DefaultStyles(), nil
+	case LightBackground:
+		l.styles, l.detectStyles = AdaptiveStyles(false), nil
+	default:
+		l.styles = DefaultStyles()
+		l.detectStyles = l.styles
+	}
+}
+
+// detectBackground replaces the default styles set by setBackground with the
+// ones for the detected background, if they weren't replaced since.
+func (l *Logger) detectBackground() {
+	if l.detectStyles == nil || (l.formatter != TextFormatter && l.formatter != HTMLFormatter) {
+		return
+	}
+	if l.styles == l.detectStyles && !hasDarkBackground(l.w.Forward) {
+		l.styles = AdaptiveStyles(false)
 	}
+	l.detectStyles = nil
 }
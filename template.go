@@ -0,0 +1,91 @@
+package log
+
+import (
+	"fmt"
+	"strings"
+)
+
+// messageTemplate is a message with named placeholders, e.g.
+// "User {user} logged in". Placeholders are replaced with the value of the
+// field with the same key, from the entry fields, then the logger fields.
+// Placeholders without a field are kept as-is, and "{{" and "}}" are
+// literal braces.
+type messageTemplate struct {
+	text    string
+	keyvals []any
+	fields  []any
+}
+
+// String returns the message with the placeholders replaced.
+func (t *messageTemplate) String() string {
+	return t.render(nil, nil)
+}
+
+// lookup returns the value of the field with the given key.
+func (t *messageTemplate) lookup(key string) (any, bool) {
+	for _, kvs := range [][]any{t.keyvals, t.fields} {
+		for i := 0; i+1 < len(kvs); i += 2 {
+			if fmt.Sprint(kvs[i]) == key {
+				return kvs[i+1], true
+			}
+		}
+	}
+	return nil, false
+}
+
+// render returns the message with the placeholders replaced. Literal text is
+// passed through text, and values through value, if they're not nil.
+func (t *messageTemplate) render(text func(string) string, value func(key string, v any) string) string {
+	if text == nil {
+		text = func(s string) string { return s }
+	}
+	if value == nil {
+		value = func(_ string, v any) string { return fmt.Sprintf("%+v", v) }
+	}
+
+	var b, lit strings.Builder
+	flush := func() {
+		if lit.Len() > 0 {
+			b.WriteString(text(lit.String()))
+			lit.Reset()
+		}
+	}
+	s := t.text
+	for len(s) > 0 {
+		i := strings.IndexAny(s, "{}")
+		if i < 0 {
+			lit.WriteString(s)
+			break
+		}
+		lit.WriteString(s[:i])
+		s = s[i:]
+		if len(s) > 1 && s[1] == s[0] {
+			// Escaped brace.
+			lit.WriteByte(s[0])
+			s = s[2:]
+			continue
+		}
+		end := strings.IndexByte(s, '}')
+		if s[0] == '}' || end < 0 {
+			lit.WriteByte(s[0])
+			s = s[1:]
+			continue
+		}
+		key := s[1:end]
+		v, ok := t.lookup(key)
+		if key == "" || !ok {
+			lit.WriteString(s[:end+1])
+		} else {
+			flush()
+			b.WriteString(value(key, v))
+		}
+		s = s[end+1:]
+	}
+	flush()
+	return b.String()
+}
+
+// template returns the message of the template with the given fields.
+func (l *Logger) template(text string, keyvals []any) *messageTemplate {
+	return &messageTemplate{text: text, keyvals: keyvals, fields: l.fields}
+}
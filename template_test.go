@@ -0,0 +1,101 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"testing"
+
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/colorprofile"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageTemplate(t *testing.T) {
+	type user struct{ Name string }
+	cases := []struct {
+		name     string
+		template string
+		keyvals  []any
+		fields   []any
+		expected string
+	}{
+		{
+			name:     "placeholders",
+			template: "User {user} logged in from {ip}",
+			keyvals:  []any{"user", "bob", "ip", "10.0.0.1"},
+			expected: "User bob logged in from 10.0.0.1",
+		},
+		{
+			name:     "missing",
+			template: "User {user} {}",
+			expected: "User {user} {}",
+		},
+		{
+			name:     "escaped",
+			template: "{{user}} {user}}} {{",
+			keyvals:  []any{"user", 1},
+			expected: "{user} 1} {",
+		},
+		{
+			name:     "unclosed",
+			template: "a } {user",
+			keyvals:  []any{"user", 1},
+			expected: "a } {user",
+		},
+		{
+			name:     "logger fields",
+			template: "{req} {user}",
+			keyvals:  []any{"user", user{"bob"}},
+			fields:   []any{"req", 42, "user", "alice"},
+			expected: "42 {Name:bob}",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			tmpl := &messageTemplate{text: c.template, keyvals: c.keyvals, fields: c.fields}
+			assert.Equal(t, c.expected, tmpl.String())
+		})
+	}
+}
+
+func TestMessageTemplateJSON(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf)
+	l.SetFormatter(JSONFormatter)
+	l.With("req", 7).Infot("User {user} got {req}", "user", "bob")
+	l.SetOptions(WithKeyNames(KeyNames{MessageTemplate: "tmpl"}))
+	l.Warnt("{n} items", "n", 2)
+	assert.Equal(t,
+		`{"level":"info","msg":"User bob got 7","msg_template":"User {user} got {req}","req":7,"user":"bob"}`+"\n"+
+			`{"level":"warn","msg":"2 items","tmpl":"{n} items","n":2}`+"\n",
+		buf.String())
+}
+
+func TestMessageTemplateText(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf)
+	l.SetColorProfile(colorprofile.TrueColor)
+	st := MinimalStyles()
+	st.Message = lipgloss.NewStyle().Bold(true)
+	st.Values["user"] = lipgloss.NewStyle().Foreground(lipgloss.Color("#ff0000"))
+	l.SetStyles(st)
+	l.Infot("User {user} logged in", "user", "bob")
+	l.Infot("multi\n{user}", "user", "bob")
+
+	assert.Equal(t,
+		"INFO "+st.Message.Render("User ")+st.Values["user"].Render("bob")+st.Message.Render(" logged in")+
+			" user="+st.Values["user"].Render("bob")+"\n"+
+			"INFO "+st.Message.Render("multi")+" user="+st.Values["user"].Render("bob")+"\n"+
+			"  │ "+st.Message.Render("bob")+"\n",
+		buf.String())
+}
+
+func TestMessageTemplateCaller(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf)
+	l.SetReportCaller(true)
+	_, _, line, _ := runtime.Caller(0)
+	l.Errort("failed {n}", "n", 1)
+	assert.Equal(t, fmt.Sprintf("ERRO <log/template_test.go:%d> failed 1 n=1\n", line+1), buf.String())
+}
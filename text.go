@@ -1,6 +1,7 @@
 package log
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"strings"
@@ -15,9 +16,116 @@ const (
 	indentSeparator = "  │ "
 )
 
-func (l *Logger) writeIndent(w io.Writer, str string, indent string, newline bool, key string) {
-	st := l.styles
+// TimestampMode is the timestamp mode for the TextFormatter.
+type TimestampMode uint8
 
+const (
+	// WallClockTimestamp reports the wall clock time of the entry. This is the
+	// default.
+	WallClockTimestamp TimestampMode = iota
+	// ElapsedTimestamp reports the time elapsed since the logger was created,
+	// e.g. "+1.234s".
+	ElapsedTimestamp
+	// DeltaTimestamp reports the time elapsed since the previous entry, e.g.
+	// "+0.012s".
+	DeltaTimestamp
+)
+
+// textState is the TextFormatter state shared by a logger and the loggers
+// derived from it. It also tracks the CSVFormatter and HTMLFormatter headers.
+type textState struct {
+	mu sync.Mutex
+	textEntryState
+
+	// now returns the current time, with a monotonic clock reading.
+	now   func() time.Time
+	start time.Time
+
+	// termWidth returns the width of the terminal the writer writes to.
+	termWidth func(w io.Writer) int
+	width     int
+	widthFd   uintptr
+	widthGen  uint64
+	widthOK   bool
+
+	// headerWritten is whether the CSVFormatter or HTMLFormatter header was
+	// written.
+	headerWritten bool
+}
+
+// textEntryState is the part of the textState that formatting an entry
+// updates.
+type textEntryState struct {
+	// The time of the previous entry.
+	last time.Time
+
+	// The widest prefix and message seen so far.
+	prefixWidth  int
+	messageWidth int
+
+	// The rendered timestamp and prefix of the previous entry in compact
+	// mode.
+	lastTimestamp string
+	lastPrefix    string
+}
+
+func newTextState() *textState {
+	now := time.Now()
+	s := &textState{
+		textEntryState: textEntryState{last: now},
+		now:            time.Now,
+		start:          now,
+	}
+	s.termWidth = s.terminalWidth
+	return s
+}
+
+// entryState returns the part of the state that formatting an entry updates.
+func (s *textState) entryState() textEntryState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.textEntryState
+}
+
+// setEntryState restores the part of the state that formatting an entry
+// updates, e.g. before formatting it again.
+func (s *textState) setEntryState(e textEntryState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.textEntryState = e
+}
+
+// elapsed returns the time elapsed since the logger was created, and since
+// the previous call to elapsed.
+func (s *textState) elapsed() (sinceStart, sincePrev time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	sinceStart, sincePrev = now.Sub(s.start), now.Sub(s.last)
+	s.last = now
+	return sinceStart, sincePrev
+}
+
+func formatElapsed(d time.Duration) string {
+	return fmt.Sprintf("+%.3fs", d.Seconds())
+}
+
+// textTimestamp returns the styled timestamp according to the logger
+// timestamp mode.
+func (l *Logger) textTimestamp(st *Styles, t time.Time) string {
+	switch l.timestampMode {
+	case ElapsedTimestamp:
+		sinceStart, _ := l.state.elapsed()
+		return st.Elapsed.Render(formatElapsed(sinceStart))
+	case DeltaTimestamp:
+		_, sincePrev := l.state.elapsed()
+		return st.Delta.Render(formatElapsed(sincePrev))
+	default:
+		return st.Timestamp.Render(fmt.Sprint(l.encodeTime(t)))
+	}
+}
+
+func writeIndent(w io.Writer, st *Styles, str string, indent string, newline bool, key string) {
 	// kindly borrowed from hclog
 	for {
 		nl := strings.IndexByte(str, '\n')
@@ -158,67 +266,155 @@ func init() {
 	}
 }
 
+// messageLines returns the styled lines of a multi-line message.
+func messageLines(st *Styles, msg string) []string {
+	lines := strings.Split(msg, "\n")
+	for i, line := range lines {
+		lines[i] = st.Message.Render(escapeStringForOutput(line, false))
+	}
+	return lines
+}
+
 func writeSpace(w io.Writer, first bool) {
 	if !first {
 		_, _ = w.Write([]byte{' '})
 	}
 }
 
-func (l *Logger) textFormatter(keyvals ...any) {
-	st := l.styles
+func (l *Logger) textFormatter(level Level, keyvals ...any) {
+	st := l.stylesForLevel(level)
 	lenKeyvals := len(keyvals)
+	align := l.alignment.enabled()
+	cols := textColumns{l: l}
+
+	var group *textGroup
+	if l.compact {
+		group = &textGroup{start: l.b.Len()}
+	}
+
+	var line *textLine
+	if l.layout == TerminalLayout {
+		if width := l.state.termWidth(l.w.Forward); width > 0 {
+			line = &textLine{width: width, start: l.b.Len()}
+		}
+	}
+
+	// The lines of multi-line messages after the first one. They're written
+	// after the single-line fields, before any multi-line values.
+	var msgLines []string
+	indentSep := st.Separator.Render(indentSeparator)
+	writeMessageLines := func(w *bytes.Buffer) {
+		if msgLines != nil {
+			w.WriteByte('\n')
+			writeLines(w, msgLines, indentSep, false)
+			msgLines = nil
+		}
+	}
 
 	for i := 0; i < lenKeyvals; i += 2 {
 		firstKey := i == 0
 		moreKeys := i < lenKeyvals-2
 
 		switch keyvals[i] {
-		case TimestampKey:
+		case l.keys.Timestamp:
 			if t, ok := keyvals[i+1].(time.Time); ok {
-				ts := t.Format(l.timeFormat)
-				ts = st.Timestamp.Render(ts)
+				ts := l.textTimestamp(st, t)
+				ts = group.timestamp(ts)
+				if line != nil {
+					line.right = append(line.right, ts)
+					continue
+				}
 				writeSpace(&l.b, firstKey)
 				l.b.WriteString(ts)
 			}
-		case LevelKey:
+		case l.keys.Level:
 			if level, ok := keyvals[i+1].(Level); ok {
 				var lvl string
 				lvlStyle, ok := st.Levels[level]
-				if !ok {
-					continue
+				switch {
+				case l.levelEncoder != nil:
+					// Use the level style colors, but not its label
+					// and width.
+					lvl = fmt.Sprint(l.levelEncoder(level))
+					lvl = lvlStyle.UnsetString().UnsetMaxWidth().Render(lvl)
+				case ok:
+					lvl = lvlStyle.String()
 				}
-
-				lvl = lvlStyle.String()
 				if lvl != "" {
-					writeSpace(&l.b, firstKey)
+					writeSpace(&l.b, line.first(&l.b, firstKey))
 					l.b.WriteString(lvl)
 				}
 			}
-		case CallerKey:
-			if caller, ok := keyvals[i+1].(string); ok {
+		case l.keys.Caller:
+			var url string
+			caller, ok := keyvals[i+1].(string)
+			if h, isLink := keyvals[i+1].(hyperlink); isLink {
+				caller, url, ok = h.text, h.url, true
+			}
+			if ok {
 				caller = fmt.Sprintf("<%s>", caller)
 				caller = st.Caller.Render(caller)
+				if url != "" {
+					caller = link(caller, url)
+				}
+				if line != nil {
+					// Callers go before timestamps.
+					line.right = append([]string{caller}, line.right...)
+					continue
+				}
 				writeSpace(&l.b, firstKey)
 				l.b.WriteString(caller)
 			}
-		case PrefixKey:
+		case l.keys.Prefix:
 			if prefix, ok := keyvals[i+1].(string); ok {
 				prefix = st.Prefix.Render(prefix + ":")
-				writeSpace(&l.b, firstKey)
+				prefix = group.prefix(prefix)
+				writeSpace(&l.b, line.first(&l.b, firstKey))
 				l.b.WriteString(prefix)
+				if align {
+					cols.prefix(stringWidth(prefix))
+				}
 			}
-		case MessageKey:
+		case l.keys.Message:
 			if msg := keyvals[i+1]; msg != nil {
-				m := fmt.Sprint(msg)
-				m = st.Message.Render(m)
-				writeSpace(&l.b, firstKey)
+				// Multi-line messages have their first line inline,
+				// and the rest in a block, like multi-line values.
+				m, rest, _ := strings.Cut(fmt.Sprint(msg), "\n")
+				multiline := rest != ""
+				if multiline {
+					// Escape the first line like the rest.
+					m = escapeStringForOutput(m, false)
+					msgLines = messageLines(st, rest)
+				}
+				if line != nil && msgLines != nil {
+					// Blocks go after the wrapped fields.
+					line.blocks.WriteByte('\n')
+					writeLines(&line.blocks, msgLines, indentSep, false)
+					msgLines = nil
+				}
+				if t, ok := msg.(*messageTemplate); ok && !multiline {
+					// Style the values of single-line templates.
+					m = t.render(func(s string) string {
+						return st.Message.Render(s)
+					}, func(key string, v any) string {
+						return st.valueStyle(key, v).Render(fmt.Sprintf("%+v", v))
+					})
+				} else {
+					m = st.Message.Render(m)
+				}
+				if align {
+					cols.beforeMessage()
+					cols.flush()
+				}
+				writeSpace(&l.b, line.first(&l.b, firstKey))
+				line.message(&l.b)
 				l.b.WriteString(m)
+				if align {
+					cols.message(stringWidth(m))
+				}
 			}
 		default:
-			sep := separator
-			indentSep := indentSeparator
-			sep = st.Separator.Render(sep)
-			indentSep = st.Separator.Render(indentSep)
+			sep := st.Separator.Render(separator)
 			key := fmt.Sprint(keyvals[i])
 			val := fmt.Sprintf("%+v", keyvals[i+1])
 			raw := val == ""
@@ -229,16 +425,17 @@ func (l *Logger) textFormatter(keyvals ...any) {
 				continue
 			}
 			actualKey := key
-			valueStyle := st.Value
-			if vs, ok := st.Values[actualKey]; ok {
-				valueStyle = vs
-			}
+			valueStyle := st.valueStyle(actualKey, keyvals[i+1])
 			if keyStyle, ok := st.Keys[key]; ok {
 				key = keyStyle.Render(key)
 			} else {
 				key = st.Key.Render(key)
 			}
 
+			if align {
+				cols.beforeField()
+			}
+
 			// Values may contain multiple lines, and that format
 			// is preserved, with each line prefixed with a "  | "
 			// to show it's part of a collection of lines.
@@ -246,27 +443,67 @@ func (l *Logger) textFormatter(keyvals ...any) {
 			// Values may also need quoting, if not all the runes
 			// in the value string are "normal", like if they
 			// contain ANSI escape sequences.
-			if strings.Contains(val, "\n") {
-				l.b.WriteString("\n  ")
-				l.b.WriteString(key)
-				l.b.WriteString(sep + "\n")
-				l.writeIndent(&l.b, val, indentSep, moreKeys, actualKey)
-			} else if !raw && needsQuoting(val) {
-				writeSpace(&l.b, firstKey)
-				l.b.WriteString(key)
-				l.b.WriteString(sep)
-				l.b.WriteString(valueStyle.Render(fmt.Sprintf(`"%s"`,
-					escapeStringForOutput(val, true))))
+			var lines []string
+			if b, ok := keyvals[i+1].([]byte); ok && len(b) > 0 {
+				for _, ln := range hexDump(b, l.hexLimit()) {
+					lines = append(lines, valueStyle.Render(ln))
+				}
+			} else if l.pretty.Enabled {
+				lines = prettyLines(keyvals[i+1], st, actualKey, l.pretty)
+			}
+			if lines != nil || strings.Contains(val, "\n") {
+				// The value starts on its own line, there's nothing
+				// to align.
+				cols.pad = 0
+				w := &l.b
+				if line != nil {
+					// Multiline values go after the wrapped fields.
+					w = &line.blocks
+					moreKeys = false
+				}
+				writeMessageLines(w)
+				w.WriteString("\n  ")
+				w.WriteString(key)
+				w.WriteString(sep + "\n")
+				if lines != nil {
+					writeLines(w, lines, indentSep, moreKeys)
+				} else {
+					writeIndent(w, st, val, indentSep, moreKeys, actualKey)
+				}
+				continue
+			}
+
+			if !raw && needsQuoting(val) {
+				val = valueStyle.Render(fmt.Sprintf(`"%s"`,
+					escapeStringForOutput(val, true)))
 			} else {
 				val = valueStyle.Render(val)
-				writeSpace(&l.b, firstKey)
-				l.b.WriteString(key)
-				l.b.WriteString(sep)
-				l.b.WriteString(val)
 			}
+			if l.hyperlinks.URLs && l.hyperlinksSupported() {
+				if k, ok := valueKind(keyvals[i+1]); ok && k == URLValue {
+					val = link(val, fmt.Sprint(keyvals[i+1]))
+				}
+			}
+			cols.flush()
+			if line != nil {
+				line.fields = append(line.fields, key+sep+val)
+				continue
+			}
+			writeSpace(&l.b, firstKey)
+			l.b.WriteString(key)
+			l.b.WriteString(sep)
+			l.b.WriteString(val)
 		}
 	}
 
+	if line != nil {
+		line.write(&l.b)
+	}
+	writeMessageLines(&l.b)
+	if group != nil {
+		group.write(&l.b, l.state)
+	}
+
 	// Add a newline to the end of the log message.
 	l.b.WriteByte('\n')
 }
@@ -328,7 +328,7 @@ func TestTextValueStyles(t *testing.T) {
 			expected: fmt.Sprintf(
 				"%s info %s%s%s\n",
 				st.Levels[ErrorLevel],
-				st.Key.Render("key1"), st.Separator.Render(separator), st.Value.Render(`"error value"`),
+				st.Key.Render("key1"), st.Separator.Render(separator), st.Kinds[ErrorValue].Render(`"error value"`),
 			),
 			msg: "info",
 			kvs: []any{"key1", errors.New("error value")},
@@ -422,3 +422,275 @@ func TestCustomLevelStyle(t *testing.T) {
 	l.Log(lvl, "foobar")
 	assert.Equal(t, "\x1b[1mFUNKY\x1b[m foobar\n", buf.String())
 }
+
+func TestTimestampModes(t *testing.T) {
+	cases := []struct {
+		name     string
+		mode     TimestampMode
+		expected string
+	}{
+		{
+			name:     "wall clock",
+			mode:     WallClockTimestamp,
+			expected: "0002/01/01 00:00:00 INFO one\n0002/01/01 00:00:00 INFO two\n",
+		},
+		{
+			name:     "elapsed",
+			mode:     ElapsedTimestamp,
+			expected: "+1.500s INFO one\n+1.750s INFO two\n",
+		},
+		{
+			name:     "delta",
+			mode:     DeltaTimestamp,
+			expected: "+1.500s INFO one\n+0.250s INFO two\n",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			l := NewWithOptions(&buf, Options{
+				ReportTimestamp: true,
+				TimestampMode:   c.mode,
+				// The time function must not affect elapsed times.
+				TimeFunction: _zeroTime,
+			})
+			now := l.state.start
+			l.state.now = func() time.Time { return now }
+
+			now = now.Add(1500 * time.Millisecond)
+			l.Info("one")
+			now = now.Add(250 * time.Millisecond)
+			l.With().Info("two")
+			assert.Equal(t, c.expected, buf.String())
+		})
+	}
+}
+
+func TestBackgroundStyles(t *testing.T) {
+	cases := []struct {
+		name     string
+		bg       Background
+		profile  colorprofile.Profile
+		expected string
+	}{
+		{
+			name:     "dark",
+			bg:       DarkBackground,
+			profile:  colorprofile.ANSI256,
+			expected: "\x1b[1;38;5;86mINFO\x1b[m info\n",
+		},
+		{
+			name:     "light",
+			bg:       LightBackground,
+			profile:  colorprofile.ANSI256,
+			expected: "\x1b[1;38;5;30mINFO\x1b[m info\n",
+		},
+		{
+			name:     "light ansi",
+			bg:       LightBackground,
+			profile:  colorprofile.ANSI,
+			expected: "\x1b[1;36mINFO\x1b[m info\n",
+		},
+		{
+			name:     "light ascii",
+			bg:       LightBackground,
+			profile:  colorprofile.Ascii,
+			expected: "\x1b[1mINFO\x1b[m info\n",
+		},
+		{
+			name:     "detect non-terminal",
+			bg:       DetectBackground,
+			profile:  colorprofile.ANSI256,
+			expected: "\x1b[1;38;5;86mINFO\x1b[m info\n",
+		},
+		{
+			name:     "default non-terminal",
+			profile:  colorprofile.ANSI256,
+			expected: "\x1b[1;38;5;86mINFO\x1b[m info\n",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			l := NewWithOptions(&buf, Options{Background: c.bg})
+			l.SetColorProfile(c.profile)
+			l.Info("info")
+			assert.Equal(t, c.expected, buf.String())
+		})
+	}
+}
+
+func TestBackgroundDetection(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf)
+	assert.Same(t, l.styles, l.detectStyles)
+
+	// Detection is deferred to the first entry of the text formatters.
+	sl := l.With("a", 1)
+	assert.Same(t, sl.styles, sl.detectStyles)
+	l.SetFormatter(JSONFormatter)
+	l.Info("json")
+	assert.NotNil(t, l.detectStyles)
+	sl.Info("text")
+	assert.Nil(t, sl.detectStyles)
+	assert.Equal(t, DefaultStyles().Levels[InfoLevel].String(), sl.styles.Levels[InfoLevel].String())
+
+	// Explicit backgrounds and styles skip detection.
+	assert.Nil(t, NewWith(&buf, WithBackground(DarkBackground)).detectStyles)
+	assert.Nil(t, NewWith(&buf, WithStyles(MinimalStyles())).detectStyles)
+	assert.Nil(t, NewWithOptions(&buf, Options{Background: LightBackground}).detectStyles)
+}
+
+func TestLevelOverrides(t *testing.T) {
+	red := lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	faint := lipgloss.NewStyle().Faint(true)
+	st := MinimalStyles()
+	st.LevelOverrides[ErrorLevel] = LevelStyles{Message: &red}
+	st.LevelOverrides[DebugLevel] = LevelStyles{Line: &faint}
+	st.LevelOverrides[WarnLevel] = LevelStyles{Key: &red, Value: &faint, Separator: &red}
+	st.Keys["bold"] = lipgloss.NewStyle().Bold(true)
+
+	var buf bytes.Buffer
+	l := New(&buf)
+	l.SetColorProfile(colorprofile.ANSI)
+	l.SetLevel(DebugLevel)
+	l.SetStyles(st)
+
+	cases := []struct {
+		name     string
+		f        func(msg any, kvs ...any)
+		expected string
+	}{
+		{
+			name:     "message",
+			f:        l.Error,
+			expected: "ERRO \x1b[31mmsg\x1b[m foo=bar \x1b[1mbold\x1b[m=baz\n",
+		},
+		{
+			name:     "line",
+			f:        l.Debug,
+			expected: "\x1b[2mDEBU\x1b[m \x1b[2mmsg\x1b[m \x1b[2mfoo\x1b[m\x1b[2m=\x1b[m\x1b[2mbar\x1b[m \x1b[1;2mbold\x1b[m\x1b[2m=\x1b[m\x1b[2mbaz\x1b[m\n",
+		},
+		{
+			name:     "key value separator",
+			f:        l.Warn,
+			expected: "WARN msg \x1b[31mfoo\x1b[m\x1b[31m=\x1b[m\x1b[2mbar\x1b[m \x1b[1mbold\x1b[m\x1b[31m=\x1b[m\x1b[2mbaz\x1b[m\n",
+		},
+		{
+			name:     "no override",
+			f:        l.Info,
+			expected: "INFO msg foo=bar \x1b[1mbold\x1b[m=baz\n",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			buf.Reset()
+			c.f("msg", "foo", "bar", "bold", "baz")
+			assert.Equal(t, c.expected, buf.String())
+		})
+	}
+
+	t.Run("cached", func(t *testing.T) {
+		assert.Same(t, l.stylesForLevel(ErrorLevel), l.stylesForLevel(ErrorLevel))
+		assert.Same(t, l.stylesForLevel(InfoLevel), l.stylesForLevel(DebugLevel+1))
+		allocs := testing.AllocsPerRun(10, func() {
+			l.stylesForLevel(ErrorLevel)
+		})
+		assert.Zero(t, allocs)
+
+		st := MinimalStyles()
+		l.SetStyles(st)
+		assert.Same(t, l.stylesForLevel(InfoLevel), l.stylesForLevel(ErrorLevel))
+	})
+}
+
+func TestMultilineMessage(t *testing.T) {
+	cases := []struct {
+		name     string
+		opts     Options
+		msg      string
+		kvs      []any
+		expected string
+	}{
+		{
+			name: "inline",
+			msg:  "first\nsecond\nthird",
+			kvs:  []any{"a", 1},
+			expected: "INFO first a=1\n" +
+				"  │ second\n" +
+				"  │ third\n",
+		},
+		{
+			name: "multiline value",
+			msg:  "first\nsecond",
+			kvs:  []any{"a", 1, "multi", "x\ny"},
+			expected: "INFO first a=1\n" +
+				"  │ second\n" +
+				"  multi=\n" +
+				"  │ x\n" +
+				"  │ y\n",
+		},
+		{
+			name: "escaped",
+			msg:  "first\nsecond\x1b[31m",
+			expected: "INFO first\n" +
+				"  │ second\\x1b[31m\n",
+		},
+		{
+			name: "escaped first line",
+			msg:  "a\x1b[31m\nb",
+			expected: "INFO a\\x1b[31m\n" +
+				"  │ b\n",
+		},
+		{
+			name: "aligned",
+			opts: Options{Alignment: Alignment{MessageWidth: 8}},
+			msg:  "first\nsecond",
+			kvs:  []any{"a", 1},
+			expected: "INFO first    a=1\n" +
+				"  │ second\n",
+		},
+		{
+			name: "split",
+			opts: Options{SplitMessages: true},
+			msg:  "first\nsecond",
+			kvs:  []any{"a", 1},
+			expected: "INFO first a=1\n" +
+				"INFO second a=1\n",
+		},
+		{
+			name:     "split trailing newline",
+			opts:     Options{SplitMessages: true},
+			msg:      "done\n",
+			expected: "INFO done\n",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			l := NewWithOptions(&buf, c.opts)
+			l.Info(c.msg, c.kvs...)
+			assert.Equal(t, c.expected, buf.String())
+		})
+	}
+}
+
+func TestMultilineMessageTerminalLayout(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWithOptions(&buf, Options{Layout: TerminalLayout})
+	l.state.termWidth = func(io.Writer) int { return 40 }
+	l.Info("first\nsecond", "a", 1, "multi", "x\ny")
+	assert.Equal(t, "INFO first a=1\n"+
+		"  │ second\n"+
+		"  multi=\n"+
+		"  │ x\n"+
+		"  │ y\n", buf.String())
+}
+
+func TestSplitMessagesJSON(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWithOptions(&buf, Options{Formatter: JSONFormatter, SplitMessages: true})
+	l.Info("first\nsecond", "a", 1)
+	assert.Equal(t, "{\"level\":\"info\",\"msg\":\"first\",\"a\":1}\n"+
+		"{\"level\":\"info\",\"msg\":\"second\",\"a\":1}\n", buf.String())
+}
@@ -0,0 +1,466 @@
+package log
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/color"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Names of the bundled themes.
+const (
+	// DefaultTheme is the name of the DefaultStyles theme.
+	DefaultTheme = "default"
+	// MinimalTheme is the name of the MinimalStyles theme.
+	MinimalTheme = "minimal"
+	// HighContrastTheme is the name of the HighContrastStyles theme.
+	HighContrastTheme = "high-contrast"
+	// EmojiTheme is the name of the EmojiStyles theme.
+	EmojiTheme = "emoji"
+	// FullWordTheme is the name of the FullWordStyles theme.
+	FullWordTheme = "full-word"
+)
+
+// ErrUnknownTheme is returned when a theme name isn't a bundled theme.
+var ErrUnknownTheme = errors.New("unknown theme")
+
+var themes = map[string]func() *Styles{
+	DefaultTheme:      DefaultStyles,
+	MinimalTheme:      MinimalStyles,
+	HighContrastTheme: HighContrastStyles,
+	EmojiTheme:        EmojiStyles,
+	FullWordTheme:     FullWordStyles,
+}
+
+// ThemeNames returns the sorted names of the bundled themes.
+func ThemeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NamedStyles returns the styles of the bundled theme with the given name.
+func NamedStyles(name string) (*Styles, error) {
+	styles, ok := themes[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownTheme, name)
+	}
+	return styles(), nil
+}
+
+// MinimalStyles returns styles without any colors or text attributes.
+func MinimalStyles() *Styles {
+	s := &Styles{
+		Levels:         map[Level]lipgloss.Style{},
+		Keys:           map[string]lipgloss.Style{},
+		Values:         map[string]lipgloss.Style{},
+		Kinds:          map[ValueKind]lipgloss.Style{},
+		LevelOverrides: map[Level]LevelStyles{},
+	}
+	for _, level := range []Level{DebugLevel, InfoLevel, WarnLevel, ErrorLevel, FatalLevel} {
+		s.Levels[level] = lipgloss.NewStyle().
+			SetString(strings.ToUpper(level.String())).
+			MaxWidth(4)
+	}
+	// Kinds are set explicitly, so that themes saved from these styles don't
+	// pick up the kind colors of the default theme when loaded.
+	for k := range valueKindNames {
+		s.Kinds[ValueKind(k)] = lipgloss.NewStyle()
+	}
+	return s
+}
+
+// HighContrastStyles returns styles with bold, bright colors and level badges
+// for better readability.
+func HighContrastStyles() *Styles {
+	badge := func(level Level, bg string) lipgloss.Style {
+		return lipgloss.NewStyle().
+			SetString(strings.ToUpper(level.String())).
+			Bold(true).
+			Width(7).
+			Padding(0, 1).
+			Foreground(lipgloss.Color("0")).
+			Background(lipgloss.Color(bg))
+	}
+
+	s := DefaultStyles()
+	s.Timestamp = lipgloss.NewStyle().Foreground(lipgloss.Color("15"))
+	s.Caller = lipgloss.NewStyle().Foreground(lipgloss.Color("14"))
+	s.Prefix = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("15"))
+	s.Message = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("15"))
+	s.Key = lipgloss.NewStyle().Foreground(lipgloss.Color("14"))
+	s.Value = lipgloss.NewStyle().Foreground(lipgloss.Color("15"))
+	s.Separator = lipgloss.NewStyle().Foreground(lipgloss.Color("7"))
+	s.NestedKey = lipgloss.NewStyle().Foreground(lipgloss.Color("14"))
+	s.Bracket = lipgloss.NewStyle().Foreground(lipgloss.Color("7"))
+	s.Levels = map[Level]lipgloss.Style{
+		DebugLevel: badge(DebugLevel, "12"),
+		InfoLevel:  badge(InfoLevel, "10"),
+		WarnLevel:  badge(WarnLevel, "11"),
+		ErrorLevel: badge(ErrorLevel, "9"),
+		FatalLevel: badge(FatalLevel, "13"),
+	}
+	return s
+}
+
+// EmojiStyles returns the default styles with emoji level labels.
+func EmojiStyles() *Styles {
+	s := DefaultStyles()
+	s.Levels = map[Level]lipgloss.Style{
+		DebugLevel: lipgloss.NewStyle().SetString("🐛"),
+		InfoLevel:  lipgloss.NewStyle().SetString("💬"),
+		WarnLevel:  lipgloss.NewStyle().SetString("🚧"),
+		ErrorLevel: lipgloss.NewStyle().SetString("🔥"),
+		FatalLevel: lipgloss.NewStyle().SetString("💀"),
+	}
+	return s
+}
+
+// FullWordStyles returns the default styles with full-word level labels,
+// e.g. "WARNING" instead of "WARN".
+func FullWordStyles() *Styles {
+	s := DefaultStyles()
+	for level, st := range s.Levels {
+		s.Levels[level] = st.
+			SetString(FullNameLevelEncoder(level).(string)).
+			UnsetMaxWidth().
+			Width(7)
+	}
+	return s
+}
+
+// Theme is a serializable representation of Styles. Themes can be loaded
+// from JSON using LoadTheme, and the struct tags also support TOML decoders.
+type Theme struct {
+	// Base is the name of a bundled theme to start from. The default is
+	// DefaultTheme.
+	Base string `json:"base,omitempty" toml:"base,omitempty"`
+
+	Timestamp *StyleSpec `json:"timestamp,omitempty" toml:"timestamp,omitempty"`
+	Elapsed   *StyleSpec `json:"elapsed,omitempty" toml:"elapsed,omitempty"`
+	Delta     *StyleSpec `json:"delta,omitempty" toml:"delta,omitempty"`
+	Caller    *StyleSpec `json:"caller,omitempty" toml:"caller,omitempty"`
+	Prefix    *StyleSpec `json:"prefix,omitempty" toml:"prefix,omitempty"`
+	Message   *StyleSpec `json:"message,omitempty" toml:"message,omitempty"`
+	Key       *StyleSpec `json:"key,omitempty" toml:"key,omitempty"`
+	Value     *StyleSpec `json:"value,omitempty" toml:"value,omitempty"`
+	Separator *StyleSpec `json:"separator,omitempty" toml:"separator,omitempty"`
+	NestedKey *StyleSpec `json:"nested_key,omitempty" toml:"nested_key,omitempty"`
+	Bracket   *StyleSpec `json:"bracket,omitempty" toml:"bracket,omitempty"`
+
+	// Levels maps level names, as encoded by LowercaseLevelEncoder, to
+	// styles. For example "debug", "warn", or "info+2" for custom levels.
+	Levels map[string]StyleSpec `json:"levels,omitempty" toml:"levels,omitempty"`
+	// Keys maps keys to styles.
+	Keys map[string]StyleSpec `json:"keys,omitempty" toml:"keys,omitempty"`
+	// Values maps keys to value styles.
+	Values map[string]StyleSpec `json:"values,omitempty" toml:"values,omitempty"`
+	// Kinds maps value kind names, e.g. "number" or "duration", to value
+	// styles.
+	Kinds map[string]StyleSpec `json:"kinds,omitempty" toml:"kinds,omitempty"`
+	// LevelOverrides maps level names, like Levels, to style overrides.
+	LevelOverrides map[string]LevelStylesSpec `json:"level_overrides,omitempty" toml:"level_overrides,omitempty"`
+	// JSON is the styles of the JSONFormatter syntax highlighting.
+	JSON *JSONStylesSpec `json:"json,omitempty" toml:"json,omitempty"`
+}
+
+// LevelStylesSpec is a serializable representation of LevelStyles.
+type LevelStylesSpec struct {
+	Message   *StyleSpec `json:"message,omitempty" toml:"message,omitempty"`
+	Key       *StyleSpec `json:"key,omitempty" toml:"key,omitempty"`
+	Value     *StyleSpec `json:"value,omitempty" toml:"value,omitempty"`
+	Separator *StyleSpec `json:"separator,omitempty" toml:"separator,omitempty"`
+	Line      *StyleSpec `json:"line,omitempty" toml:"line,omitempty"`
+}
+
+// JSONStylesSpec is a serializable representation of JSONStyles.
+type JSONStylesSpec struct {
+	Key         *StyleSpec `json:"key,omitempty" toml:"key,omitempty"`
+	String      *StyleSpec `json:"string,omitempty" toml:"string,omitempty"`
+	Number      *StyleSpec `json:"number,omitempty" toml:"number,omitempty"`
+	Bool        *StyleSpec `json:"bool,omitempty" toml:"bool,omitempty"`
+	Null        *StyleSpec `json:"null,omitempty" toml:"null,omitempty"`
+	Punctuation *StyleSpec `json:"punctuation,omitempty" toml:"punctuation,omitempty"`
+}
+
+// StyleSpec is a serializable representation of a lipgloss.Style. Colors are
+// ANSI color numbers, e.g. "63", or hex colors, e.g. "#ff5f87".
+type StyleSpec struct {
+	Foreground    string `json:"foreground,omitempty" toml:"foreground,omitempty"`
+	Background    string `json:"background,omitempty" toml:"background,omitempty"`
+	Bold          bool   `json:"bold,omitempty" toml:"bold,omitempty"`
+	Faint         bool   `json:"faint,omitempty" toml:"faint,omitempty"`
+	Italic        bool   `json:"italic,omitempty" toml:"italic,omitempty"`
+	Underline     bool   `json:"underline,omitempty" toml:"underline,omitempty"`
+	Strikethrough bool   `json:"strikethrough,omitempty" toml:"strikethrough,omitempty"`
+	Reverse       bool   `json:"reverse,omitempty" toml:"reverse,omitempty"`
+	Blink         bool   `json:"blink,omitempty" toml:"blink,omitempty"`
+	// String is the text rendered by the style, e.g. a level label.
+	String       string `json:"string,omitempty" toml:"string,omitempty"`
+	Width        int    `json:"width,omitempty" toml:"width,omitempty"`
+	MaxWidth     int    `json:"max_width,omitempty" toml:"max_width,omitempty"`
+	PaddingLeft  int    `json:"padding_left,omitempty" toml:"padding_left,omitempty"`
+	PaddingRight int    `json:"padding_right,omitempty" toml:"padding_right,omitempty"`
+}
+
+// LoadTheme reads a JSON theme from r and returns its styles.
+func LoadTheme(r io.Reader) (*Styles, error) {
+	var t Theme
+	if err := json.NewDecoder(r).Decode(&t); err != nil {
+		return nil, fmt.Errorf("failed to decode theme: %w", err)
+	}
+	return t.Styles()
+}
+
+// NewTheme returns the theme representation of the given styles.
+func NewTheme(s *Styles) Theme {
+	spec := func(st lipgloss.Style) *StyleSpec {
+		sp := NewStyleSpec(st)
+		return &sp
+	}
+	specs := func(m map[string]lipgloss.Style) map[string]StyleSpec {
+		if len(m) == 0 {
+			return nil
+		}
+		specs := make(map[string]StyleSpec, len(m))
+		for k, st := range m {
+			specs[k] = NewStyleSpec(st)
+		}
+		return specs
+	}
+
+	t := Theme{
+		Timestamp: spec(s.Timestamp),
+		Elapsed:   spec(s.Elapsed),
+		Delta:     spec(s.Delta),
+		Caller:    spec(s.Caller),
+		Prefix:    spec(s.Prefix),
+		Message:   spec(s.Message),
+		Key:       spec(s.Key),
+		Value:     spec(s.Value),
+		Separator: spec(s.Separator),
+		NestedKey: spec(s.NestedKey),
+		Bracket:   spec(s.Bracket),
+		Keys:      specs(s.Keys),
+		Values:    specs(s.Values),
+		JSON: &JSONStylesSpec{
+			Key:         spec(s.JSON.Key),
+			String:      spec(s.JSON.String),
+			Number:      spec(s.JSON.Number),
+			Bool:        spec(s.JSON.Bool),
+			Null:        spec(s.JSON.Null),
+			Punctuation: spec(s.JSON.Punctuation),
+		},
+	}
+	if len(s.Kinds) > 0 {
+		t.Kinds = make(map[string]StyleSpec, len(s.Kinds))
+		for k, st := range s.Kinds {
+			t.Kinds[k.String()] = NewStyleSpec(st)
+		}
+	}
+	if len(s.Levels) > 0 {
+		t.Levels = make(map[string]StyleSpec, len(s.Levels))
+		for level, st := range s.Levels {
+			t.Levels[LowercaseLevelEncoder(level).(string)] = NewStyleSpec(st)
+		}
+	}
+	if len(s.LevelOverrides) > 0 {
+		optSpec := func(st *lipgloss.Style) *StyleSpec {
+			if st == nil {
+				return nil
+			}
+			return spec(*st)
+		}
+		t.LevelOverrides = make(map[string]LevelStylesSpec, len(s.LevelOverrides))
+		for level, o := range s.LevelOverrides {
+			t.LevelOverrides[LowercaseLevelEncoder(level).(string)] = LevelStylesSpec{
+				Message:   optSpec(o.Message),
+				Key:       optSpec(o.Key),
+				Value:     optSpec(o.Value),
+				Separator: optSpec(o.Separator),
+				Line:      optSpec(o.Line),
+			}
+		}
+	}
+	return t
+}
+
+// Styles returns the styles of the theme. Fields that aren't set in the
+// theme keep the styles of the base theme.
+func (t Theme) Styles() (*Styles, error) {
+	base := t.Base
+	if base == "" {
+		base = DefaultTheme
+	}
+	s, err := NamedStyles(base)
+	if err != nil {
+		return nil, err
+	}
+
+	var js JSONStylesSpec
+	if t.JSON != nil {
+		js = *t.JSON
+	}
+	for _, f := range []struct {
+		spec  *StyleSpec
+		style *lipgloss.Style
+	}{
+		{t.Timestamp, &s.Timestamp},
+		{t.Elapsed, &s.Elapsed},
+		{t.Delta, &s.Delta},
+		{t.Caller, &s.Caller},
+		{t.Prefix, &s.Prefix},
+		{t.Message, &s.Message},
+		{t.Key, &s.Key},
+		{t.Value, &s.Value},
+		{t.Separator, &s.Separator},
+		{t.NestedKey, &s.NestedKey},
+		{t.Bracket, &s.Bracket},
+		{js.Key, &s.JSON.Key},
+		{js.String, &s.JSON.String},
+		{js.Number, &s.JSON.Number},
+		{js.Bool, &s.JSON.Bool},
+		{js.Null, &s.JSON.Null},
+		{js.Punctuation, &s.JSON.Punctuation},
+	} {
+		if f.spec != nil {
+			*f.style = f.spec.Style()
+		}
+	}
+
+	for name, spec := range t.Levels {
+		level, err := parseEncodedLevel(name)
+		if err != nil {
+			return nil, err
+		}
+		s.Levels[level] = spec.Style()
+	}
+	for k, spec := range t.Keys {
+		s.Keys[k] = spec.Style()
+	}
+	for k, spec := range t.Values {
+		s.Values[k] = spec.Style()
+	}
+	for name, spec := range t.Kinds {
+		k, err := ParseValueKind(name)
+		if err != nil {
+			return nil, err
+		}
+		s.Kinds[k] = spec.Style()
+	}
+
+	optStyle := func(sp *StyleSpec) *lipgloss.Style {
+		if sp == nil {
+			return nil
+		}
+		st := sp.Style()
+		return &st
+	}
+	for name, spec := range t.LevelOverrides {
+		level, err := parseEncodedLevel(name)
+		if err != nil {
+			return nil, err
+		}
+		s.LevelOverrides[level] = LevelStyles{
+			Message:   optStyle(spec.Message),
+			Key:       optStyle(spec.Key),
+			Value:     optStyle(spec.Value),
+			Separator: optStyle(spec.Separator),
+			Line:      optStyle(spec.Line),
+		}
+	}
+
+	return s, nil
+}
+
+// NewStyleSpec returns the serializable representation of the given style.
+func NewStyleSpec(st lipgloss.Style) StyleSpec {
+	return StyleSpec{
+		Foreground:    colorString(st.GetForeground()),
+		Background:    colorString(st.GetBackground()),
+		Bold:          st.GetBold(),
+		Faint:         st.GetFaint(),
+		Italic:        st.GetItalic(),
+		Underline:     st.GetUnderline(),
+		Strikethrough: st.GetStrikethrough(),
+		Reverse:       st.GetReverse(),
+		Blink:         st.GetBlink(),
+		String:        st.Value(),
+		Width:         st.GetWidth(),
+		MaxWidth:      st.GetMaxWidth(),
+		PaddingLeft:   st.GetPaddingLeft(),
+		PaddingRight:  st.GetPaddingRight(),
+	}
+}
+
+// Style returns the lipgloss.Style of the spec.
+func (sp StyleSpec) Style() lipgloss.Style {
+	st := lipgloss.NewStyle()
+	if sp.Foreground != "" {
+		st = st.Foreground(lipgloss.Color(sp.Foreground))
+	}
+	if sp.Background != "" {
+		st = st.Background(lipgloss.Color(sp.Background))
+	}
+	if sp.Bold {
+		st = st.Bold(true)
+	}
+	if sp.Faint {
+		st = st.Faint(true)
+	}
+	if sp.Italic {
+		st = st.Italic(true)
+	}
+	if sp.Underline {
+		st = st.Underline(true)
+	}
+	if sp.Strikethrough {
+		st = st.Strikethrough(true)
+	}
+	if sp.Reverse {
+		st = st.Reverse(true)
+	}
+	if sp.Blink {
+		st = st.Blink(true)
+	}
+	if sp.String != "" {
+		st = st.SetString(sp.String)
+	}
+	if sp.Width > 0 {
+		st = st.Width(sp.Width)
+	}
+	if sp.MaxWidth > 0 {
+		st = st.MaxWidth(sp.MaxWidth)
+	}
+	if sp.PaddingLeft > 0 {
+		st = st.PaddingLeft(sp.PaddingLeft)
+	}
+	if sp.PaddingRight > 0 {
+		st = st.PaddingRight(sp.PaddingRight)
+	}
+	return st
+}
+
+func colorString(c color.Color) string {
+	switch c := c.(type) {
+	case nil, lipgloss.NoColor:
+		return ""
+	case ansi.BasicColor:
+		return strconv.Itoa(int(c))
+	case ansi.IndexedColor:
+		return strconv.Itoa(int(c))
+	default:
+		r, g, b, _ := c.RGBA()
+		return fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+	}
+}
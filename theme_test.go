@@ -0,0 +1,101 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/colorprofile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamedStyles(t *testing.T) {
+	require.Equal(t, []string{DefaultTheme, EmojiTheme, FullWordTheme, HighContrastTheme, MinimalTheme}, ThemeNames())
+
+	cases := []struct {
+		name     string
+		expected string
+	}{
+		{DefaultTheme, "WARN warn foo=bar\n"},
+		{MinimalTheme, "WARN warn foo=bar\n"},
+		{HighContrastTheme, " WARN   warn foo=bar\n"},
+		{EmojiTheme, "🚧 warn foo=bar\n"},
+		{FullWordTheme, "WARNING warn foo=bar\n"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			st, err := NamedStyles(c.name)
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			l := New(&buf)
+			l.SetStyles(st)
+			l.Warn("warn", "foo", "bar")
+			assert.Equal(t, c.expected, buf.String())
+		})
+	}
+
+	_, err := NamedStyles("nope")
+	require.ErrorIs(t, err, ErrUnknownTheme)
+}
+
+func TestMinimalStylesNoColor(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf)
+	l.SetColorProfile(colorprofile.TrueColor)
+	l.SetStyles(MinimalStyles())
+	l.Error("error", "foo", "bar")
+	assert.Equal(t, "ERRO error foo=bar\n", buf.String())
+}
+
+func TestLoadTheme(t *testing.T) {
+	theme := `{
+		"base": "minimal",
+		"message": {"foreground": "#ff5f87", "bold": true},
+		"levels": {
+			"error": {"string": "ERROR", "foreground": "204"},
+			"info+2": {"string": "NOTICE"}
+		},
+		"keys": {"err": {"faint": true}},
+		"values": {"err": {"italic": true}}
+	}`
+	st, err := LoadTheme(strings.NewReader(theme))
+	require.NoError(t, err)
+
+	assert.Equal(t, lipgloss.NewStyle().Foreground(lipgloss.Color("#ff5f87")).Bold(true), st.Message)
+	assert.Equal(t, lipgloss.NewStyle().Foreground(lipgloss.Color("204")).SetString("ERROR"), st.Levels[ErrorLevel])
+	assert.Equal(t, lipgloss.NewStyle().SetString("NOTICE"), st.Levels[InfoLevel+2])
+	assert.Equal(t, MinimalStyles().Levels[WarnLevel], st.Levels[WarnLevel])
+	assert.Equal(t, lipgloss.NewStyle().Faint(true), st.Keys["err"])
+	assert.Equal(t, lipgloss.NewStyle().Italic(true), st.Values["err"])
+
+	_, err = LoadTheme(strings.NewReader(`{"levels": {"nope": {}}}`))
+	require.ErrorIs(t, err, ErrInvalidLevel)
+
+	_, err = LoadTheme(strings.NewReader(`{"base": "nope"}`))
+	require.ErrorIs(t, err, ErrUnknownTheme)
+}
+
+func TestThemeRoundTrip(t *testing.T) {
+	for _, name := range ThemeNames() {
+		t.Run(name, func(t *testing.T) {
+			st, err := NamedStyles(name)
+			require.NoError(t, err)
+			st.Keys["foo"] = lipgloss.NewStyle().Foreground(lipgloss.Color("#123456"))
+			st.Levels[InfoLevel+2] = lipgloss.NewStyle().SetString("NOTICE")
+			red := lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+			st.LevelOverrides[ErrorLevel] = LevelStyles{Message: &red, Line: &red}
+
+			data, err := json.Marshal(NewTheme(st))
+			require.NoError(t, err)
+			loaded, err := LoadTheme(bytes.NewReader(data))
+			require.NoError(t, err)
+
+			expected, actual := NewTheme(st), NewTheme(loaded)
+			assert.Equal(t, expected, actual)
+		})
+	}
+}
@@ -0,0 +1,330 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// yamlNode is a YAML value: a scalar, a mapping, or a sequence.
+type yamlNode struct {
+	kind   yamlKind
+	scalar string
+	keys   []string
+	items  []yamlNode
+}
+
+type yamlKind uint8
+
+const (
+	yamlScalar yamlKind = iota
+	yamlBlock
+	yamlMapping
+	yamlSequence
+)
+
+func (l *Logger) yamlFormatter(keyvals ...any) {
+	var entry yamlNode
+	entry.kind = yamlMapping
+	i := 0
+	for i < len(keyvals) {
+		var key, value any
+		switch kv := keyvals[i].(type) {
+		case slogAttr:
+			key, value = kv.Key, kv.Value
+			i++
+		default:
+			if i+1 >= len(keyvals) {
+				i++
+				continue
+			}
+			key, value = keyvals[i], keyvals[i+1]
+			i += 2
+		}
+		switch key {
+		case l.keys.Timestamp:
+			if t, ok := value.(time.Time); ok {
+				value = l.encodeTime(t)
+			}
+		case l.keys.Level:
+			if level, ok := value.(Level); ok {
+				value = l.encodeLevel(level)
+			}
+		}
+		entry.keys = append(entry.keys, yamlKeyString(key))
+		entry.items = append(entry.items, l.yamlValue(value))
+	}
+
+	l.b.WriteString("---\n")
+	if len(entry.keys) == 0 {
+		l.b.WriteString("{}\n")
+		return
+	}
+	writeYAMLMapping(&l.b, entry, 0, false)
+}
+
+func yamlKeyString(key any) string {
+	switch k := key.(type) {
+	case string:
+		return k
+	case fmt.Stringer:
+		return k.String()
+	case error:
+		return k.Error()
+	default:
+		return fmt.Sprint(k)
+	}
+}
+
+// yamlValue converts v to a YAML node.
+func (l *Logger) yamlValue(v any) yamlNode {
+	switch v := v.(type) {
+	case nil:
+		return yamlNode{scalar: "null"}
+	case string:
+		return yamlString(v)
+	case bool:
+		return yamlNode{scalar: strconv.FormatBool(v)}
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, uintptr:
+		return yamlNode{scalar: fmt.Sprint(v)}
+	case float32:
+		return yamlFloat(float64(v), 32)
+	case float64:
+		return yamlFloat(v, 64)
+	case json.Number:
+		return yamlNode{scalar: v.String()}
+	case time.Time:
+		return yamlString(v.Format(time.RFC3339Nano))
+	case time.Duration:
+		return yamlString(v.String())
+	case []byte:
+		return yamlString(l.encodeBytes(v))
+	case stackTrace:
+		return l.yamlValue(v.frames())
+	case slogLogValuer:
+		return l.yamlSlogValue(v.LogValue())
+	case slogValue:
+		return l.yamlSlogValue(v)
+	case error:
+		return yamlString(v.Error())
+	case fmt.Stringer:
+		return yamlString(v.String())
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		n := yamlNode{kind: yamlMapping, keys: keys}
+		for _, k := range keys {
+			n.items = append(n.items, l.yamlValue(v[k]))
+		}
+		return n
+	case []any:
+		n := yamlNode{kind: yamlSequence}
+		for _, item := range v {
+			n.items = append(n.items, l.yamlValue(item))
+		}
+		return n
+	}
+
+	// Other values are encoded like the JSONFormatter does, so that they
+	// honor json tags and json.Marshaler.
+	data, err := json.Marshal(v)
+	if err != nil {
+		return yamlString(fmt.Sprintf("%+v", v))
+	}
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var decoded any
+	if err := d.Decode(&decoded); err != nil {
+		return yamlString(fmt.Sprintf("%+v", v))
+	}
+	return l.yamlValue(decoded)
+}
+
+func (l *Logger) yamlSlogValue(v slogValue) yamlNode {
+	v = v.Resolve()
+	if v.Kind() != slogKindGroup {
+		return l.yamlValue(v.Any())
+	}
+	n := yamlNode{kind: yamlMapping}
+	for _, attr := range v.Group() {
+		n.keys = append(n.keys, attr.Key)
+		n.items = append(n.items, l.yamlSlogValue(attr.Value))
+	}
+	return n
+}
+
+func yamlFloat(f float64, bitSize int) yamlNode {
+	switch {
+	case math.IsNaN(f):
+		return yamlNode{scalar: ".nan"}
+	case math.IsInf(f, 1):
+		return yamlNode{scalar: ".inf"}
+	case math.IsInf(f, -1):
+		return yamlNode{scalar: "-.inf"}
+	}
+	return yamlNode{scalar: strconv.FormatFloat(f, 'g', -1, bitSize)}
+}
+
+// yamlString returns the node of a string. Multi-line strings are block
+// scalars, and strings that would be read as something else, like numbers
+// or booleans, are quoted.
+func yamlString(s string) yamlNode {
+	if strings.Contains(s, "\n") && yamlPrintable(s) {
+		return yamlNode{kind: yamlBlock, scalar: s}
+	}
+	return yamlNode{scalar: yamlQuote(s)}
+}
+
+// yamlQuote returns s as a plain scalar if that's safe, or double-quoted.
+func yamlQuote(s string) string {
+	if yamlPlain(s) {
+		return s
+	}
+	return strconv.Quote(s)
+}
+
+// yamlReserved are the plain scalars that YAML 1.1 and 1.2 parsers read as
+// booleans or null.
+var yamlReserved = map[string]bool{
+	"true": true, "false": true, "yes": true, "no": true, "on": true,
+	"off": true, "y": true, "n": true, "null": true, "~": true,
+}
+
+// yamlPlain reports whether s can be written as a plain scalar and read back
+// as the same string.
+func yamlPlain(s string) bool {
+	if s == "" || yamlReserved[strings.ToLower(s)] {
+		return false
+	}
+	// Anything that looks like a number, including ".5", "1e3", "0x1f",
+	// ".inf", or "1_000".
+	if c := s[0]; c >= '0' && c <= '9' || c == '.' || c == '+' || c == '-' && len(s) > 1 {
+		return false
+	}
+	if strings.ContainsRune("-?:,[]{}#&*!|>'\"%@` \t", rune(s[0])) ||
+		strings.HasSuffix(s, " ") || strings.HasSuffix(s, ":") ||
+		strings.Contains(s, ": ") || strings.Contains(s, " #") {
+		return false
+	}
+	for _, r := range s {
+		if r == '\t' || !unicode.IsPrint(r) && r != ' ' {
+			return false
+		}
+	}
+	return utf8.ValidString(s)
+}
+
+// yamlPrintable reports whether s can be written as a block scalar.
+func yamlPrintable(s string) bool {
+	for _, r := range s {
+		if r != '\n' && r != '\t' && !unicode.IsPrint(r) {
+			return false
+		}
+	}
+	return utf8.ValidString(s)
+}
+
+func writeYAMLIndent(b *bytes.Buffer, indent int) {
+	for range indent {
+		b.WriteByte(' ')
+	}
+}
+
+// writeYAMLMapping writes the mapping n at the given indentation. If inline
+// is set, the first key is written at the current position, e.g. after the
+// "- " of a sequence item.
+func writeYAMLMapping(b *bytes.Buffer, n yamlNode, indent int, inline bool) {
+	for i, key := range n.keys {
+		if i > 0 || !inline {
+			writeYAMLIndent(b, indent)
+		}
+		b.WriteString(yamlQuote(key))
+		b.WriteByte(':')
+		writeYAMLValue(b, n.items[i], indent)
+	}
+}
+
+// writeYAMLSequence writes the sequence n at the given indentation.
+func writeYAMLSequence(b *bytes.Buffer, n yamlNode, indent int) {
+	for _, item := range n.items {
+		writeYAMLIndent(b, indent)
+		b.WriteByte('-')
+		if item.kind == yamlMapping && len(item.keys) > 0 {
+			b.WriteByte(' ')
+			writeYAMLMapping(b, item, indent+2, true)
+			continue
+		}
+		writeYAMLValue(b, item, indent)
+	}
+}
+
+// writeYAMLValue writes the value n after a key or a sequence dash at the
+// given indentation.
+func writeYAMLValue(b *bytes.Buffer, n yamlNode, indent int) {
+	switch n.kind {
+	case yamlScalar:
+		b.WriteByte(' ')
+		b.WriteString(n.scalar)
+		b.WriteByte('\n')
+	case yamlBlock:
+		writeYAMLBlock(b, n.scalar, indent+2)
+	case yamlMapping:
+		if len(n.keys) == 0 {
+			b.WriteString(" {}\n")
+			return
+		}
+		b.WriteByte('\n')
+		writeYAMLMapping(b, n, indent+2, false)
+	case yamlSequence:
+		if len(n.items) == 0 {
+			b.WriteString(" []\n")
+			return
+		}
+		b.WriteByte('\n')
+		writeYAMLSequence(b, n, indent+2)
+	}
+}
+
+// writeYAMLBlock writes s as a literal block scalar, with its lines at the
+// given indentation.
+func writeYAMLBlock(b *bytes.Buffer, s string, indent int) {
+	b.WriteString(" |")
+	if s != "" && (s[0] == ' ' || s[0] == '\n') {
+		// Leading spaces would be read as indentation.
+		b.WriteString("2")
+	}
+
+	body := strings.TrimRight(s, "\n")
+	switch trailing := len(s) - len(body); {
+	case trailing == 0:
+		b.WriteString("-")
+	case trailing > 1:
+		b.WriteString("+")
+	}
+	b.WriteByte('\n')
+
+	lines := strings.Split(s, "\n")
+	if len(s)-len(body) <= 1 {
+		lines = strings.Split(body, "\n")
+	} else {
+		// Keep the trailing newlines, without the final one.
+		lines = lines[:len(lines)-1]
+	}
+	for _, line := range lines {
+		if line != "" {
+			writeYAMLIndent(b, indent)
+			b.WriteString(line)
+		}
+		b.WriteByte('\n')
+	}
+}
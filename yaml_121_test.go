@@ -0,0 +1,46 @@
+//go:build go1.21
+// +build go1.21
+
+package log
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type yamlValuer struct{ id int }
+
+func (v yamlValuer) LogValue() slog.Value {
+	return slog.GroupValue(slog.Int("id", v.id), slog.String("kind", "valuer"))
+}
+
+func TestYAMLSlog(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWithOptions(&buf, Options{Formatter: YAMLFormatter})
+	l.Info("info",
+		"group", slog.GroupValue(slog.String("a", "b"), slog.Group("inner", slog.Int("c", 1))),
+		"valuer", yamlValuer{id: 3},
+	)
+	assert.Equal(t, "---\nlevel: info\nmsg: info\n"+
+		"group:\n  a: b\n  inner:\n    c: 1\n"+
+		"valuer:\n  id: 3\n  kind: valuer\n", buf.String())
+
+	// Bare attributes and groups are expanded into keys and values.
+	buf.Reset()
+	l.Info("info",
+		slog.Group("g", slog.Int("a", 1), slog.Group("h", slog.String("b", "x"))),
+		slog.Bool("ok", true),
+	)
+	assert.Equal(t, "---\nlevel: info\nmsg: info\n"+
+		"g:\n  a: 1\n  h:\n    b: x\nok: true\n", buf.String())
+}
+
+func TestYAMLSlogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWithOptions(&buf, Options{Formatter: YAMLFormatter})
+	slog.New(l).WithGroup("g").Info("msg", slog.Group("req", slog.String("method", "GET")))
+	assert.Equal(t, "---\nlevel: info\nprefix: g\nmsg: msg\nreq:\n  method: GET\n", buf.String())
+}
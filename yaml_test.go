@@ -0,0 +1,85 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestYAML(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf)
+	l.SetFormatter(YAMLFormatter)
+	cases := []struct {
+		name     string
+		msg      string
+		kvs      []any
+		expected string
+	}{
+		{
+			name:     "simple",
+			msg:      "info",
+			expected: "---\nlevel: info\nmsg: info\n",
+		},
+		{
+			name: "scalars",
+			msg:  "info",
+			kvs: []any{
+				"int", 1, "float", 1.5, "nan", math.NaN(), "bool", true, "nil", nil,
+				"dur", time.Second, "err", errors.New("boom"),
+			},
+			expected: "---\nlevel: info\nmsg: info\nint: 1\nfloat: 1.5\nnan: .nan\n" +
+				"bool: true\nnil: null\ndur: \"1s\"\nerr: boom\n",
+		},
+		{
+			name: "quoting",
+			msg:  "info",
+			kvs: []any{
+				"num", "123", "bool", "yes", "null", "~", "empty", "",
+				"colon", "a: b", "comment", "a #b", "indicator", "*ref",
+				"space", " a", "ctrl", "a\x1bb", "plain", "hello world", "key: x", 1,
+			},
+			expected: "---\nlevel: info\nmsg: info\nnum: \"123\"\nbool: \"yes\"\n\"null\": \"~\"\n" +
+				"empty: \"\"\ncolon: \"a: b\"\ncomment: \"a #b\"\nindicator: \"*ref\"\n" +
+				"space: \" a\"\nctrl: \"a\\x1bb\"\nplain: hello world\n\"key: x\": 1\n",
+		},
+		{
+			name: "block scalars",
+			msg:  "line 1\nline 2",
+			kvs:  []any{"clip", "a\nb\n", "keep", "a\n\n", "indent", "  a\nb", "cr", "a\r\nb"},
+			expected: "---\nlevel: info\nmsg: |-\n  line 1\n  line 2\n" +
+				"clip: |\n  a\n  b\n" +
+				"keep: |+\n  a\n\n" +
+				"indent: |2-\n    a\n  b\n" +
+				"cr: \"a\\r\\nb\"\n",
+		},
+		{
+			name: "nested",
+			msg:  "info",
+			kvs: []any{
+				"struct", struct {
+					Name string   `json:"name"`
+					Tags []string `json:"tags"`
+					Skip string   `json:"-"`
+				}{Name: "x", Tags: []string{"a", "b"}},
+				"maps", []map[string]any{{"b": 2, "a": 1}, {}},
+				"empty", []int{},
+			},
+			expected: "---\nlevel: info\nmsg: info\n" +
+				"struct:\n  name: x\n  tags:\n    - a\n    - b\n" +
+				"maps:\n  - a: 1\n    b: 2\n  - {}\n" +
+				"empty: []\n",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			buf.Reset()
+			l.Info(c.msg, c.kvs...)
+			assert.Equal(t, c.expected, buf.String())
+		})
+	}
+}